@@ -0,0 +1,133 @@
+package jrnl
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// attachmentScheme is the URL scheme entries use to link to their attachments, e.g. ![](attachment:<ID>).
+const attachmentScheme = "attachment:"
+
+var attachmentLink = regexp.MustCompile(attachmentScheme + `([0-9a-f]{32})`)
+
+// ErrAttachmentNotFound is returned when an attachment's data isn't in the journal, which is
+// the case for entries synced from another device, attachments are not synced.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// Attachment is a file attached to an entry. Its data is encrypted and stored apart from the
+// entry so listing entries doesn't have to decrypt it.
+type Attachment struct {
+	ID        string
+	Name      string
+	MediaType string
+}
+
+// newAttachment returns an attachment with a new random ID.
+func newAttachment(name, mediaType string) (Attachment, error) {
+	id, err := randomID()
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	return Attachment{ID: id, Name: name, MediaType: mediaType}, nil
+}
+
+// URL returns the URL an entry's content uses to link to the attachment.
+func (a Attachment) URL() string {
+	return attachmentScheme + a.ID
+}
+
+// fileName is the name exports give the attachment's file, its ID and the extension of its name.
+func (a Attachment) fileName() string {
+	return a.ID + strings.ToLower(path.Ext(a.Name))
+}
+
+// ReadAttachment returns the decrypted data of the attachment with the given ID.
+func (j *Journal) ReadAttachment(id string) ([]byte, error) {
+	var data []byte
+	err := j.db.View(func(tx *bolt.Tx) error {
+		var err error
+		data, err = j.readAttachment(tx, id)
+		return err
+	})
+
+	return data, err
+}
+
+func (j *Journal) readAttachment(tx *bolt.Tx, id string) ([]byte, error) {
+	encrypted := tx.Bucket([]byte(attachmentBucketName)).Get([]byte(id))
+	if encrypted == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, id)
+	}
+
+	return decrypt([]byte(j.hashedPassword), encrypted)
+}
+
+func (j *Journal) hasAttachment(id string) bool {
+	found := false
+	_ = j.db.View(func(tx *bolt.Tx) error {
+		found = tx.Bucket([]byte(attachmentBucketName)).Get([]byte(id)) != nil
+		return nil
+	})
+	return found
+}
+
+// putAttachment encrypts data and stores it under id in the attachment bucket.
+func (j *Journal) putAttachment(b *bolt.Bucket, id string, data []byte) error {
+	encrypted, err := encrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return err
+	}
+
+	return b.Put([]byte(id), encrypted)
+}
+
+// deleteEntry removes the entry stored under id from the journal bucket b along with its attachments.
+func (j *Journal) deleteEntry(b *bolt.Bucket, id int) error {
+	if e, err := j.getEntry(b, id); err == nil {
+		if err = b.Tx().Bucket([]byte(dayBucketName)).Delete(j.dayIndexKey(e.CreateTime, id)); err != nil {
+			return err
+		}
+		attachments := b.Tx().Bucket([]byte(attachmentBucketName))
+		for _, a := range e.Attachments {
+			if err = attachments.Delete([]byte(a.ID)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := b.Tx().Bucket([]byte(statsBucketName)).Delete(itob(id)); err != nil {
+		return err
+	}
+
+	return b.Delete(itob(id))
+}
+
+// linkAttachments replaces the links to e's attachments in text with the result of link.
+// Links to attachments e doesn't have are left alone.
+func linkAttachments(e Entry, text string, link func(a Attachment) string) string {
+	if len(e.Attachments) == 0 {
+		return text
+	}
+
+	return attachmentLink.ReplaceAllStringFunc(text, func(m string) string {
+		if a, ok := e.attachment(strings.TrimPrefix(m, attachmentScheme)); ok {
+			return link(a)
+		}
+		return m
+	})
+}
+
+func (e Entry) attachment(id string) (Attachment, bool) {
+	for _, a := range e.Attachments {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return Attachment{}, false
+}
@@ -0,0 +1,317 @@
+package jrnl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	backupMagic      = "JRNLBAK1"
+	backupPrefix     = "jrnl-"
+	backupExt        = ".bak"
+	backupTimeLayout = "20060102T150405Z"
+	backupHeaderSize = len(backupMagic) + 8 + sha256.Size
+)
+
+var (
+	// ErrBackupCorrupt is returned when a backup archive fails its checksum.
+	ErrBackupCorrupt = errors.New("backup archive is corrupt")
+
+	// ErrBackupLocked is returned when a backup archive can't be unlocked with the given password.
+	ErrBackupLocked = errors.New("backup archive can't be unlocked with this password")
+
+	// ErrJournalInUse is returned when opening, or restoring over, a journal that is open elsewhere.
+	ErrJournalInUse = errors.New("journal is open in another jrnl")
+)
+
+// BackupConfig configures where backups are written and how many are kept.
+type BackupConfig struct {
+	Dir       string `json:"dir"`
+	OnStartup bool   `json:"onStartup"`
+	RetentionPolicy
+}
+
+// RetentionPolicy is how many daily, weekly and monthly backups rotation keeps.
+// The newest backup in each day, week or month is the one that is kept.
+type RetentionPolicy struct {
+	Daily   int `json:"daily"`
+	Weekly  int `json:"weekly"`
+	Monthly int `json:"monthly"`
+}
+
+// DefaultRetentionPolicy keeps a week of dailies, a month of weeklies and a year of monthlies.
+var DefaultRetentionPolicy = RetentionPolicy{Daily: 7, Weekly: 4, Monthly: 12}
+
+// Backup writes a compressed, encrypted and checksummed snapshot of the journal to w.
+// The snapshot is taken inside a read transaction so it is consistent even while the
+// journal is being written to.
+//
+// The archive is laid out as the magic string, the creation time as unix seconds,
+// the SHA-256 of the ciphertext and finally the AES-GCM ciphertext of the gzipped database.
+func (j *Journal) Backup(w io.Writer) error {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+
+	err := j.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(zw)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+
+	ciphertext, err := encrypt([]byte(j.hashedPassword), compressed.Bytes())
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, backupHeaderSize)
+	copy(header, backupMagic)
+	binary.BigEndian.PutUint64(header[len(backupMagic):], uint64(time.Now().Unix()))
+	sum := sha256.Sum256(ciphertext)
+	copy(header[len(backupMagic)+8:], sum[:])
+
+	if _, err = w.Write(header); err != nil {
+		return err
+	}
+	_, err = w.Write(ciphertext)
+
+	return err
+}
+
+// BackupToDir writes a new backup archive into dir and returns its path.
+// The archive is written to a temporary file first so a partial backup is never left behind.
+func (j *Journal) BackupToDir(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(dir, ".backup-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	if err = j.Backup(f); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err = f.Close(); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, backupPrefix+time.Now().UTC().Format(backupTimeLayout)+backupExt)
+	if err = os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// VerifyBackup checks the archive at path against its checksum and returns when it was created.
+// It doesn't need the password.
+func VerifyBackup(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	created, _, err := openBackup(data)
+	return created, err
+}
+
+// RestoreBackup replaces the database at dbPath with the archive at path. The archive is
+// checksummed, decrypted and opened with password before anything is replaced, and the
+// previous database, if there is one, is kept next to it under a new ".before-restore-"
+// name, which is returned. It returns ErrJournalInUse if the journal at dbPath is open.
+func RestoreBackup(path, dbPath, password string) (string, error) {
+	// hold the journal's lock until it's replaced, so a jrnl opening it in the meantime waits.
+	if _, err := os.Stat(dbPath); err == nil {
+		current, oerr := bolt.Open(dbPath, 0600, &bolt.Options{Timeout: time.Second})
+		if errors.Is(oerr, bolt.ErrTimeout) {
+			return "", ErrJournalInUse
+		}
+		if oerr != nil {
+			return "", oerr
+		}
+		defer func() {
+			_ = current.Close()
+		}()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	_, ciphertext, err := openBackup(data)
+	if err != nil {
+		return "", err
+	}
+
+	compressed, err := decrypt([]byte(hashPassword(password)), ciphertext)
+	if err != nil {
+		return "", ErrBackupLocked
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBackupCorrupt, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dbPath), ".restore-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = io.Copy(tmp, zr); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("%w: %v", ErrBackupCorrupt, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return "", err
+	}
+
+	// make sure the restored journal actually opens and unlocks before swapping it in.
+	restored, err := NewJournal(tmp.Name())
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBackupCorrupt, err)
+	}
+	err = restored.Auth(password)
+	if cerr := restored.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", ErrBackupLocked
+	}
+
+	var previous string
+	if _, err = os.Stat(dbPath); err == nil {
+		// a unique name, so restoring twice doesn't overwrite the database kept the first time.
+		kept, kerr := os.CreateTemp(filepath.Dir(dbPath), filepath.Base(dbPath)+".before-restore-*")
+		if kerr != nil {
+			return "", kerr
+		}
+		previous = kept.Name()
+		if err = kept.Close(); err != nil {
+			return "", err
+		}
+		if err = os.Rename(dbPath, previous); err != nil {
+			_ = os.Remove(previous)
+			return "", err
+		}
+	}
+
+	return previous, os.Rename(tmp.Name(), dbPath)
+}
+
+// RotateBackups deletes archives in dir that policy doesn't keep and returns their paths.
+func RotateBackups(dir string, policy RetentionPolicy) ([]string, error) {
+	names, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type backup struct {
+		path    string
+		created time.Time
+	}
+
+	var backups []backup
+	for _, name := range names {
+		n := name.Name()
+		if name.IsDir() || !strings.HasPrefix(n, backupPrefix) || !strings.HasSuffix(n, backupExt) {
+			continue
+		}
+		created, perr := time.Parse(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(n, backupPrefix), backupExt))
+		if perr != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, n), created: created})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].created.After(backups[j].created)
+	})
+
+	keep := make(map[string]bool)
+	buckets := []struct {
+		limit int
+		key   func(time.Time) string
+	}{
+		{policy.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{policy.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{policy.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+	for _, bucket := range buckets {
+		seen := make(map[string]bool)
+		for _, b := range backups {
+			if len(seen) >= bucket.limit {
+				break
+			}
+			k := bucket.key(b.created)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			keep[b.path] = true
+		}
+	}
+
+	var removed []string
+	for _, b := range backups {
+		if keep[b.path] {
+			continue
+		}
+		if err = os.Remove(b.path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, b.path)
+	}
+
+	return removed, nil
+}
+
+// openBackup checks an archive's header and checksum and returns its creation time and ciphertext.
+func openBackup(data []byte) (time.Time, []byte, error) {
+	if len(data) < backupHeaderSize || string(data[:len(backupMagic)]) != backupMagic {
+		return time.Time{}, nil, fmt.Errorf("%w: not a jrnl backup", ErrBackupCorrupt)
+	}
+
+	created := time.Unix(int64(binary.BigEndian.Uint64(data[len(backupMagic):])), 0)
+	want := data[len(backupMagic)+8 : backupHeaderSize]
+	ciphertext := data[backupHeaderSize:]
+
+	if sum := sha256.Sum256(ciphertext); !bytes.Equal(sum[:], want) {
+		return created, nil, fmt.Errorf("%w: checksum mismatch", ErrBackupCorrupt)
+	}
+
+	return created, ciphertext, nil
+}
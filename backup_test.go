@@ -0,0 +1,192 @@
+package jrnl
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_Backup(t *testing.T) {
+	f, closeFunc := mustNewTestFile(t)
+	t.Cleanup(closeFunc)
+
+	j, jCloseFunc := mustNewAuthenticatedJournal(t, f.Name())
+	t.Cleanup(jCloseFunc)
+
+	mustCreateEntry(t, j, "first entry wow")
+	mustCreateEntry(t, j, "some stuff happened")
+	want, err := j.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	path, err := j.BackupToDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyBackup(path); err != nil {
+		t.Errorf("VerifyBackup() error = %v", err)
+	}
+
+	t.Run("restore", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "db")
+		if previous, err := RestoreBackup(path, dbPath, _testPassword); err != nil || previous != "" {
+			t.Fatalf("RestoreBackup() = %q, %v, want no previous database", previous, err)
+		}
+
+		restored, err := NewJournal(dbPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := restored.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+		if err = restored.Auth(_testPassword); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := restored.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("ListEntries() after restore (-got, +want):\n%s", diff)
+		}
+	})
+
+	t.Run("restoring twice keeps both previous databases", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "db")
+		mustNewClosedJournal(t, dbPath)
+
+		var kept []string
+		for i := 0; i < 2; i++ {
+			previous, err := RestoreBackup(path, dbPath, _testPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = os.Stat(previous); err != nil {
+				t.Errorf("RestoreBackup() previous database: %v", err)
+			}
+			kept = append(kept, previous)
+		}
+		if kept[0] == kept[1] {
+			t.Errorf("RestoreBackup() kept both previous databases at %s", kept[0])
+		}
+	})
+
+	t.Run("wrong password leaves the existing database alone", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "db")
+		mustNewClosedJournal(t, dbPath)
+		existing, err := os.ReadFile(dbPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = RestoreBackup(path, dbPath, "not the password")
+		if !errors.Is(err, ErrBackupLocked) {
+			t.Errorf("RestoreBackup() error = %v, want %v", err, ErrBackupLocked)
+		}
+
+		data, err := os.ReadFile(dbPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, existing) {
+			t.Errorf("RestoreBackup() replaced the database despite failing")
+		}
+	})
+
+	t.Run("open journal", func(t *testing.T) {
+		if _, err := RestoreBackup(path, f.Name(), _testPassword); !errors.Is(err, ErrJournalInUse) {
+			t.Errorf("RestoreBackup() error = %v, want %v", err, ErrJournalInUse)
+		}
+	})
+
+	t.Run("corrupt archive", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data[len(data)-1] ^= 0xff
+
+		corrupt := filepath.Join(t.TempDir(), "corrupt.bak")
+		if err = os.WriteFile(corrupt, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = VerifyBackup(corrupt); !errors.Is(err, ErrBackupCorrupt) {
+			t.Errorf("VerifyBackup() error = %v, want %v", err, ErrBackupCorrupt)
+		}
+		if _, err = RestoreBackup(corrupt, filepath.Join(t.TempDir(), "db"), _testPassword); !errors.Is(err, ErrBackupCorrupt) {
+			t.Errorf("RestoreBackup() error = %v, want %v", err, ErrBackupCorrupt)
+		}
+	})
+}
+
+func mustNewClosedJournal(tb testing.TB, dbPath string) {
+	tb.Helper()
+
+	j, err := NewJournal(dbPath)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err = j.Close(); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+func TestRotateBackups(t *testing.T) {
+	dir := t.TempDir()
+
+	// a backup at noon and midnight every day for 60 days.
+	start := time.Date(2023, time.March, 31, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 60; i++ {
+		for _, hour := range []time.Duration{0, 12} {
+			created := start.AddDate(0, 0, -i).Add(-hour * time.Hour)
+			name := backupPrefix + created.Format(backupTimeLayout) + backupExt
+			if err := os.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "unrelated.txt"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := RotateBackups(dir, RetentionPolicy{Daily: 3, Weekly: 2, Monthly: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, name := range names {
+		got = append(got, name.Name())
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"jrnl-20230131T120000Z.bak", // newest in january
+		"jrnl-20230228T120000Z.bak", // newest in february
+		"jrnl-20230326T120000Z.bak", // newest in the previous iso week
+		"jrnl-20230329T120000Z.bak",
+		"jrnl-20230330T120000Z.bak",
+		"jrnl-20230331T120000Z.bak", // newest overall, also the newest this week and month
+		"unrelated.txt",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("RotateBackups() kept (-got, +want):\n%s", diff)
+	}
+}
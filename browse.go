@@ -0,0 +1,78 @@
+package jrnl
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AdjacentEntry returns the entry written next after the entry with the given id, or with
+// newer false the one written just before it. Entries written at the same time are ordered
+// by ID. It returns ErrEntryNotFound if there is no such entry.
+func (j *Journal) AdjacentEntry(id int, newer bool) (Entry, error) {
+	type written struct {
+		id int
+		at time.Time
+	}
+	var all []written
+	err := j.forEachEntryStats(func(i int, es entryStats) error {
+		all = append(all, written{i, es.CreateTime})
+		return nil
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+	sort.Slice(all, func(a, b int) bool {
+		if all[a].at.Equal(all[b].at) {
+			return all[a].id < all[b].id
+		}
+		return all[a].at.Before(all[b].at)
+	})
+
+	for i, w := range all {
+		if w.id != id {
+			continue
+		}
+
+		switch {
+		case newer && i+1 < len(all):
+			return j.GetEntry(all[i+1].id)
+		case !newer && i > 0:
+			return j.GetEntry(all[i-1].id)
+		case newer:
+			return Entry{}, fmt.Errorf("%w: nothing written after entry %d", ErrEntryNotFound, id)
+		default:
+			return Entry{}, fmt.Errorf("%w: nothing written before entry %d", ErrEntryNotFound, id)
+		}
+	}
+
+	return Entry{}, fmt.Errorf("%w: %d", ErrEntryNotFound, id)
+}
+
+// EntriesBetween returns the entries written from the time from up to but not including to,
+// oldest first. A zero from or to leaves that end of the range open.
+func (j *Journal) EntriesBetween(from, to time.Time) ([]Entry, error) {
+	var ids []int
+	err := j.forEachEntryStats(func(id int, es entryStats) error {
+		if (from.IsZero() || !es.CreateTime.Before(from)) && (to.IsZero() || es.CreateTime.Before(to)) {
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := j.getEntries(ids)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].CreateTime.Equal(entries[j].CreateTime) {
+			return entries[i].ID < entries[j].ID
+		}
+		return entries[i].CreateTime.Before(entries[j].CreateTime)
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,74 @@
+package jrnl
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJournal_AdjacentEntry(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	at := func(day int) time.Time {
+		return time.Date(2023, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+	// IDs aren't in the order the entries were written in, as with imported entries.
+	mustPutEntry(t, j, Entry{ID: 1, Content: "second", CreateTime: at(2)})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "first", CreateTime: at(1)})
+	mustPutEntry(t, j, Entry{ID: 3, Content: "third", CreateTime: at(3)})
+	mustPutEntry(t, j, Entry{ID: 4, Content: "fourth, at the same time", CreateTime: at(3)})
+
+	tests := []struct {
+		id     int
+		newer  bool
+		want   int
+		wantOK bool
+	}{
+		{id: 2, newer: true, want: 1, wantOK: true},
+		{id: 1, newer: true, want: 3, wantOK: true},
+		{id: 3, newer: true, want: 4, wantOK: true},
+		{id: 4, newer: true},
+		{id: 4, newer: false, want: 3, wantOK: true},
+		{id: 1, newer: false, want: 2, wantOK: true},
+		{id: 2, newer: false},
+	}
+	for _, tt := range tests {
+		e, err := j.AdjacentEntry(tt.id, tt.newer)
+		if !tt.wantOK {
+			if !errors.Is(err, ErrEntryNotFound) {
+				t.Errorf("AdjacentEntry(%d, %v) error = %v, want ErrEntryNotFound", tt.id, tt.newer, err)
+			}
+			continue
+		}
+		if err != nil || e.ID != tt.want {
+			t.Errorf("AdjacentEntry(%d, %v) = entry %d, %v, want entry %d", tt.id, tt.newer, e.ID, err, tt.want)
+		}
+	}
+
+	if _, err := j.AdjacentEntry(9, true); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("AdjacentEntry() of a missing entry error = %v, want ErrEntryNotFound", err)
+	}
+}
+
+func TestJournal_EntriesBetween(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	at := func(day int) time.Time {
+		return time.Date(2023, time.March, day, 12, 0, 0, 0, time.UTC)
+	}
+	mustPutEntry(t, j, Entry{ID: 1, Content: "second", CreateTime: at(2)})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "first", CreateTime: at(1)})
+	mustPutEntry(t, j, Entry{ID: 3, Content: "third", CreateTime: at(3)})
+
+	entries, err := j.EntriesBetween(at(1), at(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != 2 || entries[1].ID != 1 {
+		t.Errorf("EntriesBetween() = %v, want entries 2 and 1", entries)
+	}
+
+	if entries, err = j.EntriesBetween(at(2), time.Time{}); err != nil || len(entries) != 2 {
+		t.Errorf("EntriesBetween() with no end = %v, %v, want 2 entries", entries, err)
+	}
+}
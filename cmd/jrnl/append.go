@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/actatum/jrnl/tui"
+	"golang.org/x/term"
+)
+
+// appendWait is how long jrnl append waits for another jrnl to close the journal.
+const appendWait = time.Minute
+
+func appendCmd(a app, args []string) error {
+	fs := flag.NewFlagSet("append", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jrnl append [text]")
+		fmt.Fprintln(fs.Output(), "\nAdds text as a time stamped bullet to today's entry, creating it if needed.")
+		fmt.Fprintln(fs.Output(), "With no text it is read from stdin, e.g. echo 'idea' | jrnl append.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	text := strings.Join(fs.Args(), " ")
+	if fs.NArg() == 0 {
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			fs.Usage()
+			return fmt.Errorf("no text to append")
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("no text to append")
+	}
+
+	// the password is asked for before the journal is opened, so appends run from scripts at
+	// the same time keep it open only while they append and wait their turn for it.
+	pw, err := tui.EnterPasswordPrompt()
+	if err != nil {
+		return err
+	}
+	jr, _, err := tui.OpenJournalWithPassword(a.basePath, pw, appendWait)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := jr.Close(); cerr != nil {
+			log.Printf("error closing journal: %v\n", cerr)
+		}
+	}()
+
+	e, err := jr.AppendToDay(time.Now(), text)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("appended to entry %d\n", e.ID)
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/actatum/jrnl"
+	"github.com/actatum/jrnl/tui"
+)
+
+func backupCmd(a app, args []string) error {
+	cfg := jrnl.BackupConfig{}
+	if a.cfg.Backup != nil {
+		cfg = *a.cfg.Backup
+	}
+
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	fs.StringVar(&cfg.Dir, "dir", cfg.Dir, "directory to write backups to (default ~/.jrnl/backups)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path, err := tui.Backup(a.jr, cfg, a.basePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("backed up journal to %s\n", path)
+	return nil
+}
+
+func restoreCmd(a app, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jrnl restore <backup>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("a backup file is required")
+	}
+	path := fs.Arg(0)
+
+	created, err := jrnl.VerifyBackup(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restoring backup taken %s\n", created.Local().Format(time.RFC1123))
+
+	pw, err := tui.EnterPasswordPrompt()
+	if err != nil {
+		return err
+	}
+
+	dbPath := a.basePath + "/db"
+	previous, err := jrnl.RestoreBackup(path, dbPath, pw)
+	if err != nil {
+		return err
+	}
+
+	if previous == "" {
+		fmt.Println("restored journal")
+		return nil
+	}
+	fmt.Printf("restored journal, the previous database was kept at %s\n", previous)
+	return nil
+}
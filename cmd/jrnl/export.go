@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/actatum/jrnl"
+)
+
+func exportCmd(a app, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "markdown", "export format: markdown, html, json, ndjson or epub")
+	out := fs.String("out", "", "directory to export markdown or html into, or file to export json or epub into (- for stdout)")
+	group := fs.Bool("group", false, "group markdown files into year/month folders")
+	year := fs.Int("year", 0, "only export entries from this year into the epub")
+	from := fs.String("from", "", "only export entries from this day (2006-01-02) on into the epub")
+	to := fs.String("to", "", "only export entries up to and including this day (2006-01-02) into the epub")
+	tags := fs.String("tag", "", "only export entries with any of these comma separated tags into the epub")
+	title := fs.String("title", "", "title of the epub")
+	author := fs.String("author", "", "author of the epub")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return fmt.Errorf("--out is required")
+	}
+
+	switch *format {
+	case "markdown", "md":
+		res, err := a.jr.ExportMarkdown(*out, jrnl.MarkdownExportOptions{GroupByMonth: *group})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("exported to %s: %d written, %d unchanged, %d removed\n", *out, res.Written, res.Unchanged, res.Removed)
+	case "html":
+		res, err := a.jr.ExportHTML(*out)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("exported site to %s: %d written, %d unchanged, %d removed\n", *out, res.Written, res.Unchanged, res.Removed)
+		fmt.Printf("open %s/index.html in a browser to read it\n", *out)
+	case "json", "ndjson":
+		return writeOutput(*out, func(w io.Writer) error {
+			return a.jr.ExportJSON(w, jrnl.JSONExportOptions{NDJSON: *format == "ndjson"})
+		})
+	case "epub":
+		opts := jrnl.EPUBOptions{Title: *title, Author: *author}
+		for _, t := range strings.Split(*tags, ",") {
+			if t = strings.TrimPrefix(strings.TrimSpace(t), "@"); t != "" {
+				opts.Tags = append(opts.Tags, t)
+			}
+		}
+		if *year != 0 {
+			opts.From = time.Date(*year, time.January, 1, 0, 0, 0, 0, time.Local)
+			opts.To = opts.From.AddDate(1, 0, 0)
+		}
+		if *from != "" {
+			t, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --from: %w", err)
+			}
+			opts.From = t
+		}
+		if *to != "" {
+			t, err := time.ParseInLocation("2006-01-02", *to, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --to: %w", err)
+			}
+			opts.To = t.AddDate(0, 0, 1)
+		}
+
+		var n int
+		err := writeOutput(*out, func(w io.Writer) error {
+			var eerr error
+			n, eerr = a.jr.ExportEPUB(w, opts)
+			return eerr
+		})
+		if err != nil {
+			return err
+		}
+		if *out != "-" {
+			fmt.Printf("exported %d entries to %s\n", n, *out)
+		}
+	default:
+		return fmt.Errorf("unknown export format %q", *format)
+	}
+
+	return nil
+}
+
+// writeOutput calls write with path opened for writing, or with stdout if path is "-".
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err = write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/actatum/jrnl"
+)
+
+func importCmd(a app, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := fs.String("from", "json", "format to import: json (document or ndjson), jrnl-txt or jrnl-json (from jrnl.sh), dayone (zip or journal json) or markdown (a folder of notes)")
+	dryRun := fs.Bool("dry-run", false, "show what would be imported without writing anything")
+	preview := fs.Bool("preview", false, "list every entry that would be created without writing anything")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jrnl import [flags] <file|dir|->")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("a file to import is required")
+	}
+
+	opts := jrnl.ImportOptions{DryRun: *dryRun || *preview}
+	if *preview {
+		opts.Preview = printPreview
+	}
+
+	var (
+		res jrnl.ImportResult
+		err error
+	)
+	switch *from {
+	case "json", "ndjson":
+		err = readInput(fs.Arg(0), func(r io.Reader) error {
+			res, err = a.jr.ImportJSON(r, opts)
+			return err
+		})
+	case "jrnl-txt":
+		err = readInput(fs.Arg(0), func(r io.Reader) error {
+			res, err = a.jr.ImportJrnlText(r, opts)
+			return err
+		})
+	case "jrnl-json":
+		err = readInput(fs.Arg(0), func(r io.Reader) error {
+			res, err = a.jr.ImportJrnlJSON(r, opts)
+			return err
+		})
+	case "dayone":
+		err = readInput(fs.Arg(0), func(r io.Reader) error {
+			ra, size, rerr := readerAt(r)
+			if rerr != nil {
+				return rerr
+			}
+			res, err = a.jr.ImportDayOne(ra, size, opts)
+			return err
+		})
+	case "markdown", "md":
+		res, err = a.jr.ImportMarkdownDir(fs.Arg(0), opts)
+	default:
+		return fmt.Errorf("unknown import format %q", *from)
+	}
+	if err != nil {
+		return err
+	}
+
+	printImportResult(res, opts)
+	return nil
+}
+
+func printImportResult(res jrnl.ImportResult, opts jrnl.ImportOptions) {
+	verb := "imported"
+	if opts.DryRun {
+		verb = "would import"
+	}
+
+	fmt.Printf("%s %d entries, skipped %d duplicates", verb, res.Imported, res.Duplicates)
+	if res.Skipped > 0 {
+		fmt.Printf(" and %d unreadable entries", res.Skipped)
+	}
+	fmt.Println()
+}
+
+// printPreview prints a line describing an entry an import would create.
+func printPreview(e jrnl.Entry) {
+	text := e.Title
+	if text == "" {
+		text = e.Content
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > 60 {
+		text = string(r[:60]) + "…"
+	}
+
+	fmt.Printf("%s  %s", e.CreateTime.Format("2006-01-02 15:04"), text)
+	if len(e.Tags) > 0 {
+		fmt.Printf("  #%s", strings.Join(e.Tags, " #"))
+	}
+	if len(e.Attachments) > 0 {
+		fmt.Printf("  (%d attachments)", len(e.Attachments))
+	}
+	fmt.Println()
+}
+
+// readInput calls read with path opened for reading, or with stdin if path is "-".
+func readInput(path string, read func(r io.Reader) error) error {
+	if path == "-" {
+		return read(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return read(f)
+}
+
+// readerAt returns r as an io.ReaderAt and its size, reading it into memory unless it's a file.
+func readerAt(r io.Reader) (io.ReaderAt, int64, error) {
+	if f, ok := r.(*os.File); ok && f != os.Stdin {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, 0, err
+		}
+		return f, info.Size(), nil
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
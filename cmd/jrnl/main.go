@@ -3,14 +3,103 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"sort"
+	"strings"
 
+	"github.com/actatum/jrnl"
 	"github.com/actatum/jrnl/tui"
 )
 
+// app is the environment a command runs in.
+type app struct {
+	jr       *jrnl.Journal
+	cfg      jrnl.Config
+	basePath string
+}
+
+// command is a jrnl subcommand. Unless noJournal is set it runs against an unlocked journal.
+type command struct {
+	usage     string
+	run       func(a app, args []string) error
+	noJournal bool
+}
+
+var commands = map[string]command{
+	"push":     {usage: "upload local changes to the sync remote", run: pushCmd},
+	"pull":     {usage: "download changes from the sync remote", run: pullCmd},
+	"sync":     {usage: "pull then push changes, through git if it is configured", run: syncCmd},
+	"status":   {usage: "compare the journal with the sync remote", run: statusCmd},
+	"backup":   {usage: "write an encrypted backup and rotate old ones", run: backupCmd},
+	"restore":  {usage: "replace the journal with a backup", run: restoreCmd, noJournal: true},
+	"export":   {usage: "export the journal to markdown, html, json or epub", run: exportCmd},
+	"import":   {usage: "import entries from another journal", run: importCmd},
+	"watch":    {usage: "turn notes dropped into an inbox folder into entries", run: watchCmd},
+	"new":      {usage: "write a new entry, optionally from a template", run: newCmd},
+	"append":   {usage: "add a time stamped note to today's entry", run: appendCmd, noJournal: true},
+	"template": {usage: "list, show, save or delete entry templates", run: templateCmd},
+	"stats":    {usage: "summarize the writing in the journal, or with mood the moods over time", run: statsCmd},
+	"schema":   {usage: "define typed entry fields and query entries by them", run: schemaCmd},
+}
+
 func main() {
-	if err := tui.Run(); err != nil {
+	if err := run(os.Args[1:]); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return tui.Run()
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Print(usage())
+		return nil
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q\n\n%s", name, usage())
+	}
+
+	basePath, err := tui.BasePath()
+	if err != nil {
+		return err
+	}
+
+	if cmd.noJournal {
+		return cmd.run(app{basePath: basePath}, args[1:])
+	}
+
+	jr, cfg, err := tui.OpenJournal(basePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := jr.Close(); cerr != nil {
+			log.Printf("error closing journal: %v\n", cerr)
+		}
+	}()
+
+	return cmd.run(app{jr: jr, cfg: cfg, basePath: basePath}, args[1:])
+}
+
+func usage() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("usage: jrnl [command] [flags]\n\nRun without a command to open the journal.\n\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(&sb, "  %-10s %s\n", name, commands[name].usage)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/actatum/jrnl"
+	"github.com/actatum/jrnl/tui"
+)
+
+func newCmd(a app, args []string) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	template := fs.String("template", "", "start the entry from this template, see jrnl template list")
+	schema := fs.String("schema", "", "give the entry the fields of this schema, see jrnl schema list")
+	fields := make(fieldValues)
+	fs.Var(fields, "field", "set a field of the schema, as name=value, may be repeated")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jrnl new [flags] [text]")
+		fmt.Fprintln(fs.Output(), "\nWith no text the entry is written in $EDITOR.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(fields) > 0 && *schema == "" {
+		return fmt.Errorf("--field needs --schema")
+	}
+	// check the fields before anything is written in $EDITOR.
+	if *schema != "" {
+		s, err := a.jr.GetSchema(*schema)
+		if err != nil {
+			return err
+		}
+		if _, err = s.Parse(fields); err != nil {
+			return err
+		}
+	}
+
+	var start string
+	if *template != "" {
+		t, err := a.jr.FindTemplate(tui.TemplateDir(a.basePath), *template)
+		if err != nil {
+			return err
+		}
+		if start, err = a.jr.RenderTemplate(t, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if a.cfg.DailyNotes {
+		today, err := a.jr.EntryForDay(time.Now())
+		switch {
+		case err == nil:
+			if *schema != "" {
+				return fmt.Errorf("daily notes are on and today's entry %d is already written, set its fields in the TUI", today.ID)
+			}
+			return addToToday(a, today, start, strings.Join(fs.Args(), " "))
+		case !errors.Is(err, jrnl.ErrEntryNotFound):
+			return err
+		}
+	}
+
+	content := start + strings.Join(fs.Args(), " ")
+	if fs.NArg() == 0 {
+		var err error
+		if content, err = tui.EditText(start); err != nil {
+			return err
+		}
+		if strings.TrimSpace(content) == "" || content == strings.TrimSuffix(start, "\n") {
+			fmt.Println("nothing written, no entry created")
+			return nil
+		}
+	}
+
+	e, err := a.jr.CreateEntryWithFields(content, *schema, fields)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created entry %d\n", e.ID)
+	return nil
+}
+
+// addToToday adds to today's entry instead of creating another with daily notes on. Text is
+// appended like jrnl append does, with no text the entry is edited in $EDITOR with start added
+// to the end of it.
+func addToToday(a app, today jrnl.Entry, start, text string) error {
+	if text != "" {
+		e, err := a.jr.AppendToDay(time.Now(), start+text)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("appended to today's entry %d\n", e.ID)
+		return nil
+	}
+
+	before := today.Content
+	if start != "" && before != "" {
+		before = strings.TrimRight(before, "\n") + "\n\n" + start
+	} else {
+		before += start
+	}
+	content, err := tui.EditText(before)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(content) == "" || content == today.Content || content == strings.TrimSuffix(before, "\n") {
+		fmt.Printf("nothing written, today's entry %d is unchanged\n", today.ID)
+		return nil
+	}
+
+	if _, err = a.jr.EditEntry(today.ID, content); err != nil {
+		return err
+	}
+	fmt.Printf("edited today's entry %d\n", today.ID)
+	return nil
+}
+
+func templateCmd(a app, args []string) error {
+	fs := flag.NewFlagSet("template", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jrnl template list")
+		fmt.Fprintln(fs.Output(), "       jrnl template show <name>")
+		fmt.Fprintln(fs.Output(), "       jrnl template save <name> [file|-]")
+		fmt.Fprintln(fs.Output(), "       jrnl template delete <name>")
+		fmt.Fprintln(fs.Output(), "\nTemplates are saved encrypted in the journal, .md files in")
+		fmt.Fprintf(fs.Output(), "%s are templates too. Save without a file to write one in $EDITOR.\n", tui.TemplateDir(a.basePath))
+		fmt.Fprintln(fs.Output(), "Templates can use {{date}}, {{time}}, {{weekday}}, {{prompt}} and {{todos}}, the unfinished tasks of the previous entry.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	dir := tui.TemplateDir(a.basePath)
+	switch {
+	case fs.Arg(0) == "list" && fs.NArg() == 1:
+		templates, err := a.jr.ListTemplates(dir)
+		if err != nil {
+			return err
+		}
+		for _, t := range templates {
+			fmt.Println(t.Name)
+		}
+		return nil
+	case fs.Arg(0) == "show" && fs.NArg() == 2:
+		t, err := a.jr.FindTemplate(dir, fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		fmt.Print(t.Content)
+		return nil
+	case fs.Arg(0) == "save" && (fs.NArg() == 2 || fs.NArg() == 3):
+		t, err := a.jr.FindTemplate(dir, fs.Arg(1))
+		if err != nil && !errors.Is(err, jrnl.ErrTemplateNotFound) {
+			return err
+		}
+		t.Name = fs.Arg(1)
+
+		if fs.NArg() == 3 {
+			err = readInput(fs.Arg(2), func(r io.Reader) error {
+				data, rerr := io.ReadAll(r)
+				t.Content = string(data)
+				return rerr
+			})
+		} else {
+			t.Content, err = tui.EditText(t.Content)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = a.jr.SaveTemplate(t); err != nil {
+			return err
+		}
+		fmt.Printf("saved template %s\n", t.Name)
+		return nil
+	case fs.Arg(0) == "delete" && fs.NArg() == 2:
+		if err := a.jr.DeleteTemplate(fs.Arg(1)); err != nil {
+			return err
+		}
+		fmt.Printf("deleted template %s\n", fs.Arg(1))
+		return nil
+	}
+
+	fs.Usage()
+	return fmt.Errorf("unknown template command")
+}
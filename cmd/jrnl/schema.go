@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/actatum/jrnl"
+	"github.com/actatum/jrnl/tui"
+)
+
+// fieldValues collects repeated name=value flags.
+type fieldValues map[string]string
+
+func (v fieldValues) String() string {
+	pairs := make([]string, 0, len(v))
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v fieldValues) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("want name=value, got %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
+func schemaCmd(a app, args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jrnl schema list")
+		fmt.Fprintln(fs.Output(), "       jrnl schema show <name>")
+		fmt.Fprintln(fs.Output(), "       jrnl schema save <name> [file|-]")
+		fmt.Fprintln(fs.Output(), "       jrnl schema delete <name>")
+		fmt.Fprintln(fs.Output(), "       jrnl schema query <name> [field=value|field<value...]")
+		fmt.Fprintln(fs.Output(), "\nA schema gives entries of one kind typed fields, number, scale (1-10), enum,")
+		fmt.Fprintln(fs.Output(), "bool or duration. It is written as JSON, save without a file to write one in $EDITOR:")
+		fmt.Fprintln(fs.Output(), `  {"fields": [{"name": "kind", "type": "enum", "options": ["run", "swim"]}, {"name": "distance", "type": "number"}]}`)
+		fmt.Fprintln(fs.Output(), "Query compares fields with =, !=, <, <=, > and >=.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case fs.Arg(0) == "list" && fs.NArg() == 1:
+		schemas, err := a.jr.ListSchemas()
+		if err != nil {
+			return err
+		}
+		for _, s := range schemas {
+			fmt.Println(s.Name)
+		}
+		return nil
+	case fs.Arg(0) == "show" && fs.NArg() == 2:
+		s, err := a.jr.GetSchema(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		return printSchema(os.Stdout, s)
+	case fs.Arg(0) == "save" && (fs.NArg() == 2 || fs.NArg() == 3):
+		return saveSchema(a, fs.Arg(1), fs.Arg(2))
+	case fs.Arg(0) == "delete" && fs.NArg() == 2:
+		if err := a.jr.DeleteSchema(fs.Arg(1)); err != nil {
+			return err
+		}
+		fmt.Printf("deleted schema %s\n", fs.Arg(1))
+		return nil
+	case fs.Arg(0) == "query" && fs.NArg() >= 2:
+		return querySchema(a, fs.Arg(1), fs.Args()[2:])
+	}
+
+	fs.Usage()
+	return fmt.Errorf("unknown schema command")
+}
+
+func printSchema(w io.Writer, s jrnl.Schema) error {
+	s.Name = ""
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// saveSchema saves the schema read from path, or written in $EDITOR if path is empty.
+func saveSchema(a app, name, path string) error {
+	var data []byte
+	if path != "" {
+		err := readInput(path, func(r io.Reader) error {
+			var rerr error
+			data, rerr = io.ReadAll(r)
+			return rerr
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		s, err := a.jr.GetSchema(name)
+		if errors.Is(err, jrnl.ErrSchemaNotFound) {
+			s = jrnl.Schema{Fields: []jrnl.Field{{Name: "rating", Type: jrnl.FieldScale}}}
+		} else if err != nil {
+			return err
+		}
+
+		var b strings.Builder
+		if err = printSchema(&b, s); err != nil {
+			return err
+		}
+		text, err := tui.EditText(b.String())
+		if err != nil {
+			return err
+		}
+		data = []byte(text)
+	}
+
+	var s jrnl.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("reading schema: %w", err)
+	}
+	s.Name = name
+	if err := a.jr.SaveSchema(s); err != nil {
+		return err
+	}
+
+	fmt.Printf("saved schema %s\n", name)
+	return nil
+}
+
+// querySchema prints the fields of the entries following the schema that pass the filters, one entry a line.
+func querySchema(a app, name string, args []string) error {
+	s, err := a.jr.GetSchema(name)
+	if err != nil {
+		return err
+	}
+
+	var filters []jrnl.FieldFilter
+	for _, arg := range args {
+		ff, ferr := jrnl.ParseFieldFilter(arg)
+		if ferr != nil {
+			return ferr
+		}
+		filters = append(filters, ff)
+	}
+	entries, err := a.jr.FilterEntries(name, filters...)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	header := []string{"id", "date"}
+	for _, f := range s.Fields {
+		header = append(header, f.Name)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, e := range entries {
+		row := []string{fmt.Sprint(e.ID), e.CreateTime.Format("2006-01-02 15:04")}
+		for _, f := range s.Fields {
+			row = append(row, e.Fields[f.Name])
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/actatum/jrnl"
+)
+
+// statsWidth is how many characters wide the stats charts are.
+const statsWidth = 60
+
+func statsCmd(a app, args []string) error {
+	if len(args) > 0 && args[0] == "mood" {
+		return moodStatsCmd(a, args[1:])
+	}
+
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the stats as JSON, with counts for every day and month")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jrnl stats [flags]")
+		fmt.Fprintln(fs.Output(), "       jrnl stats mood [flags]")
+		fmt.Fprintln(fs.Output(), "\nSummarizes the writing in the journal, or with mood the moods recorded with entries.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("unknown stats command %q", fs.Arg(0))
+	}
+
+	s, err := a.jr.Stats(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	}
+
+	fmt.Printf("%d entries, %d words on %d days, %.0f words a day\n", s.Entries, s.Words, s.Days, s.WordsPerDay)
+	fmt.Printf("streak: %d days, longest %d days\n", s.CurrentStreak, s.LongestStreak)
+	if s.Entries == 0 {
+		return nil
+	}
+
+	fmt.Println("\nby hour")
+	hours := make([]float64, len(s.ByHour))
+	for h, n := range s.ByHour {
+		hours[h] = float64(n)
+	}
+	fmt.Printf("  %s\n  0     6     12    18   23\n", jrnl.Sparkline(hours, 0, maxOf(hours)))
+
+	fmt.Println("\nby weekday")
+	for i := 1; i <= 7; i++ {
+		wd := time.Weekday(i % 7)
+		fmt.Printf("  %-9s %d\n", wd, s.ByWeekday[wd])
+	}
+
+	fmt.Println("\nby month")
+	for _, m := range s.ByMonth {
+		fmt.Printf("  %-9s %4d entries %7d words\n", m.Month, m.Entries, m.Words)
+	}
+
+	return nil
+}
+
+func maxOf(values []float64) float64 {
+	var m float64
+	for _, v := range values {
+		m = math.Max(m, v)
+	}
+	return m
+}
+
+func moodStatsCmd(a app, args []string) error {
+	fs := flag.NewFlagSet("stats mood", flag.ContinueOnError)
+	from := fs.String("from", "", "only count moods from this day (2006-01-02) on")
+	to := fs.String("to", "", "only count moods up to and including this day (2006-01-02)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jrnl stats mood [flags]")
+		fmt.Fprintln(fs.Output(), "\nSummarizes the moods recorded with entries.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var start, end time.Time
+	if *from != "" {
+		t, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+		start = t
+	}
+	if *to != "" {
+		t, err := time.ParseInLocation("2006-01-02", *to, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+
+	r, err := a.jr.MoodReport(start, end)
+	if err != nil {
+		return err
+	}
+	if len(r.Points) == 0 {
+		fmt.Println("no moods recorded")
+		return nil
+	}
+
+	scale := a.cfg.Mood.MoodScale()
+	level := func(avg float64) string {
+		i := int(math.Round(avg)) - 1
+		if i < 0 || i >= len(scale) {
+			return fmt.Sprintf("%.1f", avg)
+		}
+		return fmt.Sprintf("%.1f %s", avg, scale[i])
+	}
+
+	fmt.Printf("%s to %s: %d moods, on average %s\n\n", r.From.Format("2006-01-02"), r.To.AddDate(0, 0, -1).Format("2006-01-02"), len(r.Points), level(r.Average))
+	fmt.Println(jrnl.Sparkline(r.Daily(statsWidth), 1, float64(len(scale))))
+
+	fmt.Println("\nby weekday")
+	for i := 1; i <= 7; i++ {
+		wd := time.Weekday(i % 7)
+		if avg := r.ByWeekday[wd]; avg != 0 {
+			fmt.Printf("  %-9s %s\n", wd, level(avg))
+		}
+	}
+
+	fmt.Println("\nby month")
+	for _, m := range r.ByMonth {
+		fmt.Printf("  %-9s %-16s %d moods\n", m.Month.Format("2006-01"), level(m.Average), m.Count)
+	}
+
+	return nil
+}
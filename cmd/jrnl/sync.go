@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/actatum/jrnl"
+)
+
+func pushCmd(a app, _ []string) error {
+	res, err := a.jr.Push(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("pushed %d entries, deleted %d\n", res.Pushed, res.Deleted)
+	return nil
+}
+
+func pullCmd(a app, _ []string) error {
+	res, err := a.jr.Pull(context.Background())
+	if err != nil {
+		return err
+	}
+
+	printPullResult(res)
+	return nil
+}
+
+func syncCmd(a app, _ []string) error {
+	sync := a.jr.Sync
+	if a.jr.GitEnabled() {
+		sync = a.jr.SyncGit
+	}
+
+	res, err := sync(context.Background())
+	if err != nil {
+		return err
+	}
+
+	printPullResult(res)
+	fmt.Printf("pushed %d entries\n", res.Pushed)
+	return nil
+}
+
+func statusCmd(a app, _ []string) error {
+	status, err := a.jr.SyncStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	last := "never"
+	if !status.LastSync.IsZero() {
+		last = status.LastSync.Local().Format(time.RFC1123)
+	}
+
+	fmt.Printf("last synced: %s\n", last)
+	fmt.Printf("to push:     %d\n", status.ToPush)
+	fmt.Printf("to pull:     %d\n", status.ToPull)
+	fmt.Printf("conflicts:   %d\n", status.Conflicts)
+	return nil
+}
+
+func printPullResult(res jrnl.SyncResult) {
+	fmt.Printf("pulled %d entries, deleted %d\n", res.Pulled, res.Deleted)
+	for _, c := range res.Conflicts {
+		fmt.Printf("conflict: entry %d was changed on another device, their version was saved as entry %d\n", c.EntryID, c.CopyID)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/actatum/jrnl"
+)
+
+func watchCmd(a app, args []string) error {
+	cfg := jrnl.InboxConfig{}
+	if a.cfg.Inbox != nil {
+		cfg = *a.cfg.Inbox
+	}
+
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.StringVar(&cfg.Dir, "dir", cfg.Dir, "inbox directory to watch (default ~/.jrnl/inbox)")
+	fs.StringVar(&cfg.Quarantine, "quarantine", cfg.Quarantine, "directory files that can't be ingested are moved to (default <dir>/.quarantine)")
+	interval := fs.Duration("interval", 2*time.Second, "how often to check the inbox")
+	once := fs.Bool("once", false, "ingest what is in the inbox now and exit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = a.basePath + "/inbox"
+	}
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return err
+	}
+
+	if *once {
+		files, err := a.jr.IngestInbox(cfg)
+		for _, f := range files {
+			printInboxFile(f)
+		}
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("watching %s, press ctrl+c to stop\n", cfg.Dir)
+	return a.jr.WatchInbox(ctx, cfg, *interval, printInboxFile)
+}
+
+func printInboxFile(f jrnl.InboxFile) {
+	name := filepath.Base(f.Path)
+	switch {
+	case f.QuarantinedTo != "":
+		fmt.Printf("%s: %v, moved to %s\n", name, f.Err, f.QuarantinedTo)
+	case f.Err != nil:
+		fmt.Printf("%s: %v\n", name, f.Err)
+	case f.Duplicate:
+		fmt.Printf("%s: already in the journal, removed\n", name)
+	default:
+		fmt.Printf("%s: added entry for %s\n", name, f.Entry.CreateTime.Format("2006-01-02 15:04"))
+	}
+}
@@ -0,0 +1,39 @@
+package jrnl
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// Config holds optional settings read from the journal's config file.
+type Config struct {
+	Sync   *S3Config     `json:"sync,omitempty"`
+	Git    *GitConfig    `json:"git,omitempty"`
+	Backup *BackupConfig `json:"backup,omitempty"`
+	Inbox  *InboxConfig  `json:"inbox,omitempty"`
+	Mood   *MoodConfig   `json:"mood,omitempty"`
+	// DailyNotes keeps to one entry per day, creating an entry opens the day's entry if there is one.
+	DailyNotes bool `json:"dailyNotes,omitempty"`
+	// OnThisDay opens the TUI on the entries written on today's date in earlier years, when there are any.
+	OnThisDay bool `json:"onThisDay,omitempty"`
+}
+
+// LoadConfig reads the config file at path. A missing file yields the zero Config.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
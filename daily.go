@@ -0,0 +1,109 @@
+package jrnl
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// dailyBulletLayout is the time stamp AppendToDay starts each bullet with.
+const dailyBulletLayout = "15:04"
+
+// EntryForDay returns the first entry created on the calendar day of day, in day's location.
+// It returns ErrEntryNotFound if nothing was written that day.
+func (j *Journal) EntryForDay(day time.Time) (Entry, error) {
+	if err := j.ensureStatsIndex(); err != nil {
+		return Entry{}, err
+	}
+
+	var e Entry
+	err := j.db.View(func(tx *bolt.Tx) error {
+		var err error
+		e, err = j.entryForDay(tx, day)
+		return err
+	})
+
+	return e, err
+}
+
+// entryForDay finds the entry for day with the entry stats, so only the entry found is
+// decrypted. The stats must be up to date, see ensureStatsIndex.
+func (j *Journal) entryForDay(tx *bolt.Tx, day time.Time) (Entry, error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	var (
+		found   int
+		created time.Time
+	)
+	err := tx.Bucket([]byte(statsBucketName)).ForEach(func(k, v []byte) error {
+		es, err := j.decodeEntryStats(v)
+		if err != nil {
+			return err
+		}
+		if !es.CreateTime.Before(start) && es.CreateTime.Before(end) && (found == 0 || es.CreateTime.Before(created)) {
+			found, created = btoi(k), es.CreateTime
+		}
+		return nil
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+	if found == 0 {
+		return Entry{}, ErrEntryNotFound
+	}
+
+	return j.getEntry(tx.Bucket([]byte(journalBucketName)), found)
+}
+
+// AppendToDay adds text as a bullet time stamped with now to the entry for now's day, creating
+// the entry if there isn't one yet. Lines after the first are indented to stay in the bullet.
+// The entry is read and written in a single transaction so appends running at the same time
+// never lose each other's text.
+func (j *Journal) AppendToDay(now time.Time, text string) (Entry, error) {
+	bullet := "- " + now.Format(dailyBulletLayout) + " " + strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n  ")
+
+	var (
+		e      Entry
+		action = "Edit"
+	)
+	if err := j.ensureStatsIndex(); err != nil {
+		return Entry{}, err
+	}
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(journalBucketName))
+
+		var err error
+		e, err = j.entryForDay(tx, now)
+		switch {
+		case err == nil:
+			if e.Content != "" && !strings.HasSuffix(e.Content, "\n") {
+				e.Content += "\n"
+			}
+			e.Content += bullet
+			e.UpdateTime = now
+		case errors.Is(err, ErrEntryNotFound):
+			id, serr := b.NextSequence()
+			if serr != nil {
+				return serr
+			}
+			e = Entry{ID: int(id), Content: bullet, CreateTime: now, UpdateTime: now}
+			action = "Create"
+		default:
+			return err
+		}
+
+		return j.putEntry(b, e)
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if err = j.recordGitHistory(action, e.ID); err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
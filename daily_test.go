@@ -0,0 +1,170 @@
+package jrnl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_EntryForDay(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	day := time.Date(2023, time.January, 5, 0, 0, 0, 0, time.UTC)
+	mustPutEntry(t, j, Entry{ID: 1, Content: "the night before", CreateTime: day.Add(-time.Minute)})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "evening", CreateTime: day.Add(20 * time.Hour)})
+	mustPutEntry(t, j, Entry{ID: 3, Content: "morning", CreateTime: day.Add(8 * time.Hour)})
+	mustPutEntry(t, j, Entry{ID: 4, Content: "the next day", CreateTime: day.AddDate(0, 0, 1)})
+
+	e, err := j.EntryForDay(day.Add(23 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Content != "morning" {
+		t.Errorf("EntryForDay() = %q, want the first entry of the day", e.Content)
+	}
+
+	// midnight UTC on the 5th is still the 4th in New York.
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	if e, err = j.EntryForDay(time.Date(2023, time.January, 4, 12, 0, 0, 0, ny)); err != nil || e.Content != "the night before" {
+		t.Errorf("EntryForDay() in New York = %q, %v", e.Content, err)
+	}
+
+	if _, err = j.EntryForDay(day.AddDate(0, 0, 2)); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("EntryForDay() of an empty day = %v, want ErrEntryNotFound", err)
+	}
+}
+
+func TestJournal_AppendToDay(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	morning := time.Date(2023, time.January, 5, 9, 30, 0, 0, time.UTC)
+	first, err := j.AppendToDay(morning, "coffee\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := j.AppendToDay(morning.Add(3*time.Hour), "lunch with\nan old friend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = j.AppendToDay(morning.AddDate(0, 0, 1), "a new day"); err != nil {
+		t.Fatal(err)
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("expected both appends in one entry, got entries %d and %d", first.ID, second.ID)
+	}
+	want := "- 09:30 coffee\n- 12:30 lunch with\n  an old friend"
+	if diff := cmp.Diff(second.Content, want); diff != "" {
+		t.Errorf("AppendToDay() content (-got, +want):\n%s", diff)
+	}
+	if !second.CreateTime.Equal(morning) || !second.UpdateTime.Equal(morning.Add(3*time.Hour)) {
+		t.Errorf("expected the entry created at the first append and updated at the last, got %v and %v", second.CreateTime, second.UpdateTime)
+	}
+
+	entries, err := j.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected an entry per day, got %d entries", len(entries))
+	}
+}
+
+func TestJournal_AppendToDay_Concurrent(t *testing.T) {
+	j := mustNewTestJournal(t)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := j.AppendToDay(now, fmt.Sprintf("note %d", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	e, err := j.EntryForDay(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if !strings.Contains(e.Content, fmt.Sprintf("note %d\n", i)) && !strings.HasSuffix(e.Content, fmt.Sprintf("note %d", i)) {
+			t.Errorf("note %d was lost:\n%s", i, e.Content)
+		}
+	}
+	if entries, _ := j.ListEntries(); len(entries) != 1 {
+		t.Errorf("expected a single entry, got %d", len(entries))
+	}
+}
+
+func TestNewJournalWait(t *testing.T) {
+	// each jrnl append opens the journal itself, so they take turns for its lock.
+	f, closeFunc := mustNewTestFile(t)
+	t.Cleanup(closeFunc)
+
+	first, err := NewJournal(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = first.CreatePassword(_testPassword); err != nil {
+		t.Fatal(err)
+	}
+	if err = first.Auth(_testPassword); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if _, err = first.AppendToDay(now, "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = NewJournalWait(f.Name(), 10*time.Millisecond); !errors.Is(err, ErrJournalInUse) {
+		t.Fatalf("NewJournalWait() of an open journal = %v, want ErrJournalInUse", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		second, oerr := NewJournalWait(f.Name(), 10*time.Second)
+		if oerr != nil {
+			done <- oerr
+			return
+		}
+		defer func() {
+			_ = second.Close()
+		}()
+		if oerr = second.Auth(_testPassword); oerr != nil {
+			done <- oerr
+			return
+		}
+		_, oerr = second.AppendToDay(now, "second")
+		done <- oerr
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	if err = first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	j, jCloseFunc := mustNewAuthenticatedJournal(t, f.Name())
+	t.Cleanup(jCloseFunc)
+	e, err := j.EntryForDay(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(e.Content, "first") || !strings.Contains(e.Content, "second") {
+		t.Errorf("expected both appends in the entry, got:\n%s", e.Content)
+	}
+}
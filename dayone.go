@@ -0,0 +1,231 @@
+package jrnl
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dayOneMoment matches a link to an entry's photo or other media in Day One markdown,
+// e.g. dayone-moment://<identifier> or dayone-moment:/video/<identifier>.
+var dayOneMoment = regexp.MustCompile(`dayone-moment:/{1,2}(?:[A-Za-z]+/)?([0-9A-Fa-f]{32})`)
+
+// dayOneMedia lists the fields of a Day One entry listing its media and the export folders holding the files.
+var dayOneMedia = []struct {
+	field string
+	dir   string
+}{
+	{"photos", "photos"},
+	{"videos", "videos"},
+	{"audios", "audios"},
+	{"pdfAttachments", "pdfs"},
+}
+
+// dayOneMediaFile is an item of one of a Day One entry's media fields.
+type dayOneMediaFile struct {
+	Identifier string `json:"identifier"`
+	MD5        string `json:"md5"`
+	Type       string `json:"type"`
+}
+
+// ImportDayOne reads a Day One JSON export, either the zip file Day One exports or a journal
+// JSON file from inside one, and adds its entries to the journal. Entries keep their creation
+// time in their own time zone, tags and starred flags. Photos and other media in the zip
+// become encrypted attachments that the entry's content links to. Every other field, such as
+// location and weather, is kept as is in the entry's metadata so nothing is lost.
+func (j *Journal) ImportDayOne(r io.ReaderAt, size int64, opts ImportOptions) (ImportResult, error) {
+	magic := make([]byte, 4)
+	if _, err := r.ReadAt(magic, 0); err != nil && !errors.Is(err, io.EOF) {
+		return ImportResult{}, err
+	}
+
+	if !bytes.Equal(magic, []byte("PK\x03\x04")) {
+		entries, err := readDayOneJournal(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return ImportResult{}, err
+		}
+		next, _ := dayOneEntryReader(entries, nil)
+		return j.importEntries(next, nil, nil, opts, attachmentBatchSize)
+	}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	var (
+		files    = make(map[string]*zip.File)
+		entries  []map[string]json.RawMessage
+		journals int
+	)
+	for _, f := range zr.File {
+		files[f.Name] = f
+		if path.Dir(f.Name) != "." || path.Ext(f.Name) != ".json" {
+			continue
+		}
+
+		rc, oerr := f.Open()
+		if oerr != nil {
+			return ImportResult{}, oerr
+		}
+		journal, rerr := readDayOneJournal(rc)
+		_ = rc.Close()
+		if rerr != nil {
+			return ImportResult{}, fmt.Errorf("%s: %w", f.Name, rerr)
+		}
+		entries = append(entries, journal...)
+		journals++
+	}
+	if journals == 0 {
+		return ImportResult{}, fmt.Errorf("not a Day One export: no journal JSON files in the zip")
+	}
+
+	next, media := dayOneEntryReader(entries, files)
+	readAttachment := func(id string) ([]byte, error) {
+		f, ok := media[id]
+		if !ok {
+			return nil, fmt.Errorf("attachment %s not in the export", id)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = rc.Close()
+		}()
+		return io.ReadAll(rc)
+	}
+
+	return j.importEntries(next, readAttachment, nil, opts, attachmentBatchSize)
+}
+
+// readDayOneJournal reads the entries of a Day One journal JSON file.
+func readDayOneJournal(r io.Reader) ([]map[string]json.RawMessage, error) {
+	var journal struct {
+		Entries []map[string]json.RawMessage `json:"entries"`
+	}
+	if err := json.NewDecoder(r).Decode(&journal); err != nil {
+		return nil, fmt.Errorf("not a Day One export: %w", err)
+	}
+
+	return journal.Entries, nil
+}
+
+// dayOneEntryReader returns a function yielding each of entries in turn for importEntries,
+// and the map it fills with the zip file behind each attachment it creates.
+func dayOneEntryReader(entries []map[string]json.RawMessage, files map[string]*zip.File) (func() (Entry, error), map[string]*zip.File) {
+	media := make(map[string]*zip.File)
+	return func() (Entry, error) {
+		if len(entries) == 0 {
+			return Entry{}, io.EOF
+		}
+		fields := entries[0]
+		entries = entries[1:]
+
+		return dayOneEntry(fields, files, media)
+	}, media
+}
+
+// dayOneEntry converts a Day One entry. The fields jrnl has a place for are taken out of
+// fields and the rest become the entry's metadata. Media found in files is attached and
+// recorded in media.
+func dayOneEntry(fields map[string]json.RawMessage, files, media map[string]*zip.File) (Entry, error) {
+	var (
+		e        Entry
+		created  time.Time
+		modified time.Time
+		timeZone string
+		uuid     string
+	)
+	if err := json.Unmarshal(fields["creationDate"], &created); err != nil {
+		return e, fmt.Errorf("%w: bad creationDate: %v", errSkipEntry, err)
+	}
+	for name, v := range map[string]interface{}{
+		"text":         &e.Content,
+		"modifiedDate": &modified,
+		"tags":         &e.Tags,
+		"starred":      &e.Starred,
+		"timeZone":     &timeZone,
+		"uuid":         &uuid,
+	} {
+		if raw, ok := fields[name]; ok {
+			if err := json.Unmarshal(raw, v); err != nil {
+				return e, fmt.Errorf("%w: bad %s: %v", errSkipEntry, name, err)
+			}
+		}
+	}
+
+	loc := time.UTC
+	if l, err := time.LoadLocation(timeZone); err == nil && timeZone != "" {
+		loc = l
+	}
+	e.CreateTime = created.In(loc)
+	if !modified.IsZero() {
+		e.UpdateTime = modified.In(loc)
+	}
+
+	// media is linked by its identifier, which is only unique within the entry.
+	links := make(map[string]string)
+	for _, m := range dayOneMedia {
+		var items []dayOneMediaFile
+		if raw, ok := fields[m.field]; !ok || json.Unmarshal(raw, &items) != nil {
+			continue
+		}
+		for _, item := range items {
+			name := item.MD5 + "." + item.Type
+			f, ok := files[m.dir+"/"+name]
+			if !ok {
+				continue
+			}
+
+			sum := sha256.Sum256([]byte(uuid + "/" + created.String() + "/" + item.Identifier))
+			a := Attachment{
+				// derived rather than random so importing the same export twice finds the duplicates.
+				ID:        hex.EncodeToString(sum[:16]),
+				Name:      name,
+				MediaType: mediaType(name),
+			}
+			e.Attachments = append(e.Attachments, a)
+			media[a.ID] = f
+			links[strings.ToUpper(item.Identifier)] = a.URL()
+		}
+	}
+	e.Content = dayOneMoment.ReplaceAllStringFunc(e.Content, func(m string) string {
+		id := dayOneMoment.FindStringSubmatch(m)[1]
+		if url, ok := links[strings.ToUpper(id)]; ok {
+			return url
+		}
+		return m
+	})
+
+	for _, name := range []string{"text", "creationDate", "modifiedDate", "tags", "starred"} {
+		delete(fields, name)
+	}
+	if len(fields) > 0 {
+		e.Metadata = fields
+	}
+
+	return e, nil
+}
+
+// mediaType guesses a file's media type from its extension.
+func mediaType(name string) string {
+	t := mime.TypeByExtension(strings.ToLower(path.Ext(name)))
+	if t == "" {
+		return "application/octet-stream"
+	}
+	if i := strings.IndexByte(t, ';'); i >= 0 {
+		t = t[:i]
+	}
+	return t
+}
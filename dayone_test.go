@@ -0,0 +1,171 @@
+package jrnl
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const dayOneJournal = `{
+  "metadata": {"version": "1.0"},
+  "entries": [
+    {
+      "uuid": "2B7F2A64E5C34A58B4FB3A3C3D2A1F10",
+      "creationDate": "2023-01-05T14:30:00Z",
+      "modifiedDate": "2023-01-05T15:00:00Z",
+      "timeZone": "America/New_York",
+      "starred": true,
+      "tags": ["travel", "family"],
+      "text": "# Lisbon\n\n![](dayone-moment://0D2F1B0E6A9C4E6B9E8C2B4A1F3D5E7C)\n\nTrams everywhere.",
+      "location": {"localityName": "Lisbon", "latitude": 38.72, "longitude": -9.14},
+      "weather": {"conditionsDescription": "Sunny", "temperatureCelsius": 16},
+      "photos": [{"identifier": "0D2F1B0E6A9C4E6B9E8C2B4A1F3D5E7C", "md5": "5d41402abc4b2a76b9719d911017c592", "type": "jpeg"}]
+    },
+    {
+      "uuid": "9A1C7E55D3B84D26A1F0E2C4B6D8F0A2",
+      "creationDate": "2023-01-06T08:00:00Z",
+      "text": "Home again."
+    },
+    {
+      "uuid": "11111111111111111111111111111111",
+      "creationDate": "not a date",
+      "text": "broken"
+    }
+  ]
+}`
+
+func TestJournal_ImportDayOne(t *testing.T) {
+	photo := []byte("\xff\xd8\xff\xe0 not really a jpeg")
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for name, data := range map[string][]byte{
+		"Journal.json": []byte(dayOneJournal),
+		"photos/5d41402abc4b2a76b9719d911017c592.jpeg": photo,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	j := mustNewTestJournal(t)
+
+	res, err := j.ImportDayOne(bytes.NewReader(archive.Bytes()), int64(archive.Len()), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res, ImportResult{Imported: 2, Skipped: 1}); diff != "" {
+		t.Errorf("ImportDayOne() (-got, +want):\n%s", diff)
+	}
+
+	entries, err := j.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	lisbon := entries[1]
+
+	if _, offset := lisbon.CreateTime.Zone(); offset != -5*60*60 || lisbon.CreateTime.Hour() != 9 {
+		t.Errorf("expected the creation time in New York time, got %v", lisbon.CreateTime)
+	}
+	if !lisbon.Starred || !cmp.Equal(lisbon.Tags, []string{"travel", "family"}) {
+		t.Errorf("expected starred entry tagged travel and family, got %+v", lisbon)
+	}
+	for _, field := range []string{"uuid", "timeZone", "location", "weather", "photos"} {
+		if _, ok := lisbon.Metadata[field]; !ok {
+			t.Errorf("expected %s to be kept in the metadata", field)
+		}
+	}
+	var weather struct {
+		Conditions string `json:"conditionsDescription"`
+	}
+	if err = json.Unmarshal(lisbon.Metadata["weather"], &weather); err != nil || weather.Conditions != "Sunny" {
+		t.Errorf("weather metadata = %s, %v", lisbon.Metadata["weather"], err)
+	}
+	if _, ok := lisbon.Metadata["text"]; ok {
+		t.Errorf("text should not be duplicated into the metadata")
+	}
+
+	if len(lisbon.Attachments) != 1 {
+		t.Fatalf("expected the photo to be attached, got %+v", lisbon.Attachments)
+	}
+	a := lisbon.Attachments[0]
+	if a.MediaType != "image/jpeg" || !strings.Contains(lisbon.Content, "![]("+a.URL()+")") {
+		t.Errorf("expected the photo link to point at the attachment, got %+v in %q", a, lisbon.Content)
+	}
+	data, err := j.ReadAttachment(a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, photo) {
+		t.Errorf("ReadAttachment() = %q, want %q", data, photo)
+	}
+
+	res, err = j.ImportDayOne(bytes.NewReader(archive.Bytes()), int64(archive.Len()), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res, ImportResult{Duplicates: 2, Skipped: 1}); diff != "" {
+		t.Errorf("re-import (-got, +want):\n%s", diff)
+	}
+
+	t.Run("exports carry the photo", func(t *testing.T) {
+		var book bytes.Buffer
+		if _, err = j.ExportEPUB(&book, EPUBOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		files := validateEPUB(t, book.Bytes())
+		if files["OEBPS/images/"+a.fileName()] != string(photo) {
+			t.Errorf("expected the photo in the book")
+		}
+		if !strings.Contains(files["OEBPS/chapter-2023-01.xhtml"], `src="images/`+a.fileName()+`"`) {
+			t.Errorf("expected the chapter to show the photo:\n%s", files["OEBPS/chapter-2023-01.xhtml"])
+		}
+
+		var export bytes.Buffer
+		if err = j.ExportJSON(&export, JSONExportOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		dst := mustNewTestJournal(t)
+		if _, err = dst.ImportJSON(&export, ImportOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		copied, err := dst.ReadAttachment(a.ID)
+		if err != nil || !bytes.Equal(copied, photo) {
+			t.Errorf("JSON round trip lost the photo: %q, %v", copied, err)
+		}
+	})
+
+	t.Run("deleting the entry deletes the photo", func(t *testing.T) {
+		if err = j.DeleteEntry(lisbon.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = j.ReadAttachment(a.ID); err == nil {
+			t.Errorf("expected the attachment to be deleted with its entry")
+		}
+	})
+
+	t.Run("journal JSON", func(t *testing.T) {
+		dst := mustNewTestJournal(t)
+		res, err := dst.ImportDayOne(strings.NewReader(dayOneJournal), int64(len(dayOneJournal)), ImportOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(res, ImportResult{Imported: 2, Skipped: 1}); diff != "" {
+			t.Errorf("ImportDayOne() (-got, +want):\n%s", diff)
+		}
+	})
+}
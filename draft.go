@@ -0,0 +1,89 @@
+package jrnl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Draft is editor text that hasn't been saved to its entry yet. The editor autosaves drafts
+// so nothing is lost if it is closed or the terminal dies, and they can be recovered later.
+// Drafts are encrypted like entries but aren't synced.
+type Draft struct {
+	ID string
+	// EntryID is the entry the draft is an edit of, or 0 for a new entry.
+	EntryID    int
+	Content    string
+	UpdateTime time.Time
+}
+
+// SaveDraft stores d, giving it an ID if it doesn't have one yet, and returns it as saved.
+func (j *Journal) SaveDraft(d Draft) (Draft, error) {
+	if d.ID == "" {
+		id, err := randomID()
+		if err != nil {
+			return Draft{}, err
+		}
+		d.ID = id
+	}
+	d.UpdateTime = time.Now()
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return Draft{}, err
+	}
+	encrypted, err := encrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return Draft{}, err
+	}
+
+	err = j.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(draftBucketName)).Put([]byte(d.ID), encrypted)
+	})
+	if err != nil {
+		return Draft{}, err
+	}
+
+	return d, nil
+}
+
+// ListDrafts lists the drafts waiting to be recovered, most recently saved first.
+func (j *Journal) ListDrafts() ([]Draft, error) {
+	drafts := make([]Draft, 0)
+
+	err := j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(draftBucketName)).ForEach(func(k, v []byte) error {
+			data, err := decrypt([]byte(j.hashedPassword), v)
+			if err != nil {
+				return err
+			}
+
+			var d Draft
+			if err = json.Unmarshal(data, &d); err != nil {
+				return fmt.Errorf("draft %s: %w", k, err)
+			}
+			drafts = append(drafts, d)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].UpdateTime.After(drafts[j].UpdateTime)
+	})
+
+	return drafts, nil
+}
+
+// DeleteDraft removes the draft with the given ID. Deleting a draft that doesn't exist is not an error.
+func (j *Journal) DeleteDraft(id string) error {
+	return j.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(draftBucketName)).Delete([]byte(id))
+	})
+}
@@ -0,0 +1,64 @@
+package jrnl
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestJournal_SaveDraft(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	first, err := j.SaveDraft(Draft{Content: "half a thought"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == "" || first.UpdateTime.IsZero() {
+		t.Errorf("expected the draft to get an ID and save time, got %+v", first)
+	}
+
+	e := mustCreateEntry(t, j, "written yesterday")
+	second, err := j.SaveDraft(Draft{EntryID: e.ID, Content: "written yesterday, and today"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.Content = "half a thought, finished"
+	if first, err = j.SaveDraft(first); err != nil {
+		t.Fatal(err)
+	}
+
+	drafts, err := j.ListDrafts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(drafts, []Draft{first, second}, cmpopts.EquateApproxTime(time.Millisecond)); diff != "" {
+		t.Errorf("ListDrafts() (-got, +want):\n%s", diff)
+	}
+
+	err = j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(draftBucketName)).ForEach(func(_, v []byte) error {
+			if bytes.Contains(v, []byte("thought")) {
+				t.Errorf("draft stored in plaintext")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = j.DeleteDraft(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err = j.DeleteDraft(first.ID); err != nil {
+		t.Errorf("deleting a missing draft: %v", err)
+	}
+	if drafts, _ = j.ListDrafts(); len(drafts) != 1 || drafts[0].ID != second.ID {
+		t.Errorf("expected only the second draft left, got %+v", drafts)
+	}
+}
@@ -0,0 +1,332 @@
+package jrnl
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+const (
+	epubMimetype    = "application/epub+zip"
+	epubEntryLayout = "Monday, 2 January 2006 at 3:04PM"
+	epubRangeLayout = "January 2006"
+)
+
+// ErrNothingToExport is returned when an export's filters don't match any entries.
+var ErrNothingToExport = errors.New("no entries to export")
+
+//go:embed epub
+var epubFS embed.FS
+
+var epubTemplates = template.Must(template.ParseFS(epubFS, "epub/book.tmpl"))
+
+// epubAttachmentLink matches a markdown link or image pointing at an attachment.
+var epubAttachmentLink = regexp.MustCompile(`(!?)\[([^\]]*)\]\(` + attachmentScheme + `([0-9a-f]{32})\)`)
+
+// epubImageTypes are the image media types every EPUB 3 reader supports.
+var epubImageTypes = map[string]bool{
+	"image/gif":     true,
+	"image/jpeg":    true,
+	"image/png":     true,
+	"image/svg+xml": true,
+	"image/webp":    true,
+}
+
+// EPUBOptions configures ExportEPUB.
+type EPUBOptions struct {
+	// Title is the book's title, "Journal" followed by the years it covers if empty.
+	Title string
+	// Author is the book's creator, left out if empty.
+	Author string
+	// Language is the book's BCP 47 language tag, "en" if empty.
+	Language string
+	// From and To select entries created at or after From and before To, either may be zero.
+	From time.Time
+	To   time.Time
+	// Tags selects entries with any of these tags, or every entry if empty.
+	Tags []string
+}
+
+type epubEntry struct {
+	Anchor string
+	Href   string
+	Title  string
+	HTML   template.HTML
+}
+
+type epubImage struct {
+	ID           string
+	Href         string
+	MediaType    string
+	attachmentID string
+}
+
+type epubChapter struct {
+	ID      string
+	Href    string
+	Title   string
+	Remote  bool
+	Entries []*epubEntry
+}
+
+type epubBook struct {
+	Identifier  string
+	Title       string
+	Author      string
+	Language    string
+	Date        string
+	Description string
+	Modified    string
+	PageTitle   string
+	Chapters    []*epubChapter
+	Images      []epubImage
+	Chapter     *epubChapter
+}
+
+// epubDoc is a document of the book and the template that renders it.
+type epubDoc struct {
+	path string
+	name string
+	page epubBook
+}
+
+// ExportEPUB writes the entries selected by opts to w as an EPUB 3 e-book with a title
+// page, a table of contents and a chapter per month, oldest first. It returns how many
+// entries the book holds, or ErrNothingToExport if opts selects none.
+//
+// The book's identifier is derived from its title and filters, so re-exporting the same
+// selection replaces the earlier copy on an e-reader instead of adding a second one.
+func (j *Journal) ExportEPUB(w io.Writer, opts EPUBOptions) (int, error) {
+	entries, err := j.ListEntries()
+	if err != nil {
+		return 0, err
+	}
+
+	selected := entries[:0]
+	for _, e := range entries {
+		if !opts.From.IsZero() && e.CreateTime.Before(opts.From) {
+			continue
+		}
+		if !opts.To.IsZero() && !e.CreateTime.Before(opts.To) {
+			continue
+		}
+		if len(opts.Tags) > 0 && !hasAnyTag(e, opts.Tags) {
+			continue
+		}
+		selected = append(selected, e)
+	}
+	if len(selected) == 0 {
+		return 0, ErrNothingToExport
+	}
+	sort.SliceStable(selected, func(a, b int) bool {
+		return selected[a].CreateTime.Before(selected[b].CreateTime)
+	})
+
+	book, err := newEPUBBook(selected, opts, j.hasAttachment)
+	if err != nil {
+		return 0, err
+	}
+
+	zw := zip.NewWriter(w)
+
+	// the mimetype must come first and be stored uncompressed so readers can sniff it.
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return 0, err
+	}
+	if _, err = io.WriteString(mw, epubMimetype); err != nil {
+		return 0, err
+	}
+
+	for _, name := range []string{"container.xml", "style.css"} {
+		data, rerr := epubFS.ReadFile("epub/" + name)
+		if rerr != nil {
+			return 0, rerr
+		}
+		path := "OEBPS/" + name
+		if name == "container.xml" {
+			path = "META-INF/" + name
+		}
+		if err = writeZipFile(zw, path, data); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, img := range book.Images {
+		data, rerr := j.ReadAttachment(img.attachmentID)
+		if rerr != nil {
+			return 0, rerr
+		}
+		if err = writeZipFile(zw, "OEBPS/"+img.Href, data); err != nil {
+			return 0, err
+		}
+	}
+
+	docs := []epubDoc{
+		{"OEBPS/content.opf", "content.opf", book},
+		{"OEBPS/nav.xhtml", "nav.xhtml", book.withPage("Contents", nil)},
+		{"OEBPS/title.xhtml", "title.xhtml", book.withPage(book.Title, nil)},
+	}
+	for _, c := range book.Chapters {
+		docs = append(docs, epubDoc{"OEBPS/" + c.Href, "chapter.xhtml", book.withPage(c.Title, c)})
+	}
+
+	for _, d := range docs {
+		var buf bytes.Buffer
+		buf.WriteString(xml.Header)
+		if d.name != "content.opf" {
+			buf.WriteString("<!DOCTYPE html>\n")
+		}
+		if err = epubTemplates.ExecuteTemplate(&buf, d.name, d.page); err != nil {
+			return 0, err
+		}
+		if err = writeZipFile(zw, d.path, buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(selected), zw.Close()
+}
+
+// newEPUBBook renders entries, sorted oldest first, into chapters and fills in the book's metadata.
+// stored reports whether an attachment's data is in the journal to be embedded.
+func newEPUBBook(entries []Entry, opts EPUBOptions, stored func(id string) bool) (epubBook, error) {
+	first, last := entries[0].CreateTime, entries[len(entries)-1].CreateTime
+
+	book := epubBook{
+		Title:    opts.Title,
+		Author:   opts.Author,
+		Language: opts.Language,
+		Date:     first.Format("2006-01-02"),
+		Modified: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if book.Language == "" {
+		book.Language = "en"
+	}
+	if book.Title == "" {
+		book.Title = "Journal " + fmt.Sprint(first.Year())
+		if last.Year() != first.Year() {
+			book.Title += fmt.Sprintf("–%d", last.Year())
+		}
+	}
+	book.Description = first.Format(epubRangeLayout)
+	if last.Format(epubRangeLayout) != book.Description {
+		book.Description += " – " + last.Format(epubRangeLayout)
+	}
+	book.Description += fmt.Sprintf(", %d entries", len(entries))
+
+	h := sha256.Sum256([]byte(book.Title + "\x00" + opts.From.UTC().String() + "\x00" + opts.To.UTC().String() + "\x00" + strings.Join(opts.Tags, ",")))
+	// lay the hash out as a version 5 style UUID.
+	h[6] = h[6]&0x0f | 0x50
+	h[8] = h[8]&0x3f | 0x80
+	book.Identifier = fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(goldmarkhtml.WithXHTML()),
+	)
+
+	for _, e := range entries {
+		month := e.CreateTime.Format("2006-01")
+		if len(book.Chapters) == 0 || book.Chapters[len(book.Chapters)-1].ID != "chapter-"+month {
+			book.Chapters = append(book.Chapters, &epubChapter{
+				ID:    "chapter-" + month,
+				Href:  "chapter-" + month + ".xhtml",
+				Title: e.CreateTime.Format(epubRangeLayout),
+			})
+		}
+		c := book.Chapters[len(book.Chapters)-1]
+
+		var buf bytes.Buffer
+		if err := md.Convert([]byte(book.embedAttachments(e, stored)), &buf); err != nil {
+			return book, err
+		}
+		body := buf.String()
+		if strings.Contains(body, `src="http://`) || strings.Contains(body, `src="https://`) {
+			c.Remote = true
+		}
+
+		anchor := fmt.Sprintf("entry-%d", e.ID)
+		c.Entries = append(c.Entries, &epubEntry{
+			Anchor: anchor,
+			Href:   c.Href + "#" + anchor,
+			Title:  e.CreateTime.Format(epubEntryLayout),
+			// goldmark escapes raw HTML in entries unless it is explicitly told not to.
+			HTML: template.HTML(body),
+		})
+	}
+
+	return book, nil
+}
+
+// embedAttachments returns e's markdown with its images linked to copies inside the book.
+// Links to other attachments, which e-readers can't open, and to attachments that aren't
+// stored are replaced with their text.
+func (b *epubBook) embedAttachments(e Entry, stored func(id string) bool) string {
+	return epubAttachmentLink.ReplaceAllStringFunc(e.Markdown(), func(m string) string {
+		parts := epubAttachmentLink.FindStringSubmatch(m)
+		a, ok := e.attachment(parts[3])
+		if !ok {
+			return m
+		}
+		if parts[1] == "" || !epubImageTypes[a.MediaType] || !stored(a.ID) {
+			if parts[2] != "" {
+				return parts[2]
+			}
+			return a.Name
+		}
+
+		href := "images/" + a.fileName()
+		if !b.hasImage(href) {
+			b.Images = append(b.Images, epubImage{ID: "image-" + a.ID, Href: href, MediaType: a.MediaType, attachmentID: a.ID})
+		}
+		return "![" + parts[2] + "](" + href + ")"
+	})
+}
+
+func (b *epubBook) hasImage(href string) bool {
+	for _, img := range b.Images {
+		if img.Href == href {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyTag(e Entry, tags []string) bool {
+	for _, t := range tags {
+		if e.HasTag(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b epubBook) withPage(title string, chapter *epubChapter) epubBook {
+	b.PageTitle = title
+	b.Chapter = chapter
+	return b
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
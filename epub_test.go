@@ -0,0 +1,295 @@
+package jrnl
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournal_ExportEPUB(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	day := time.Date(2022, time.December, 31, 22, 0, 0, 0, time.UTC)
+	mustPutEntry(t, j, Entry{ID: 1, Content: "last year", CreateTime: day, UpdateTime: day})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "# Fireworks\n\ncaf&eacute; &copy; <b>raw</b>\n\n- [x] done\n\n---", CreateTime: day.Add(4 * time.Hour), UpdateTime: day.Add(4 * time.Hour)})
+	mustPutEntry(t, j, Entry{ID: 3, Content: "a quiet day", CreateTime: day.AddDate(0, 0, 5), UpdateTime: day.AddDate(0, 0, 5)})
+	mustPutEntry(t, j, Entry{ID: 4, Content: "spring", CreateTime: day.AddDate(0, 3, 0), UpdateTime: day.AddDate(0, 3, 0), Tags: []string{"Garden"}})
+	mustPutEntry(t, j, Entry{ID: 5, Content: "next year", CreateTime: day.AddDate(1, 0, 1), UpdateTime: day.AddDate(1, 0, 1)})
+
+	var buf bytes.Buffer
+	n, err := j.ExportEPUB(&buf, EPUBOptions{
+		Author: "Ada",
+		From:   time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 entries in the book, got %d", n)
+	}
+
+	files := validateEPUB(t, buf.Bytes())
+
+	opf := files["OEBPS/content.opf"]
+	for _, want := range []string{"<dc:title>Journal 2023</dc:title>", "<dc:creator>Ada</dc:creator>", "January 2023 – March 2023, 3 entries"} {
+		if !strings.Contains(opf, want) {
+			t.Errorf("content.opf missing %q:\n%s", want, opf)
+		}
+	}
+
+	for _, name := range []string{"OEBPS/chapter-2023-01.xhtml", "OEBPS/chapter-2023-03.xhtml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("expected chapter %s", name)
+		}
+	}
+	if len(files) != 8 {
+		t.Errorf("expected 8 files, got %d", len(files))
+	}
+
+	january := files["OEBPS/chapter-2023-01.xhtml"]
+	for _, want := range []string{"<h1>Fireworks</h1>", "café ©", "raw HTML omitted", "<hr />"} {
+		if !strings.Contains(january, want) {
+			t.Errorf("chapter-2023-01.xhtml missing %q:\n%s", want, january)
+		}
+	}
+	if strings.Contains(january, "<b>raw</b>") {
+		t.Errorf("chapter-2023-01.xhtml contains raw html from the entry")
+	}
+	if strings.Index(january, "entry-2") > strings.Index(january, "entry-3") {
+		t.Errorf("chapters should list entries oldest first")
+	}
+	for _, skipped := range []string{"last year", "next year"} {
+		for name, data := range files {
+			if strings.Contains(data, skipped) {
+				t.Errorf("%s contains an entry outside the date range", name)
+			}
+		}
+	}
+
+	var again bytes.Buffer
+	if _, err = j.ExportEPUB(&again, EPUBOptions{Author: "Ada", From: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatal(err)
+	}
+	if id := epubIdentifier(t, files); id != epubIdentifier(t, validateEPUB(t, again.Bytes())) {
+		t.Errorf("re-exporting the same range should keep the identifier %s", id)
+	}
+
+	var tagged bytes.Buffer
+	if n, err = j.ExportEPUB(&tagged, EPUBOptions{Tags: []string{"garden"}}); err != nil {
+		t.Fatal(err)
+	}
+	if files = validateEPUB(t, tagged.Bytes()); n != 1 || !strings.Contains(files["OEBPS/chapter-2023-03.xhtml"], "spring") {
+		t.Errorf("expected only the garden entry, got %d entries", n)
+	}
+
+	_, err = j.ExportEPUB(io.Discard, EPUBOptions{From: time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)})
+	if !errors.Is(err, ErrNothingToExport) {
+		t.Errorf("expected ErrNothingToExport, got %v", err)
+	}
+}
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type epubPackage struct {
+	Version          string `xml:"version,attr"`
+	UniqueIdentifier string `xml:"unique-identifier,attr"`
+	Metadata         struct {
+		Identifiers []struct {
+			ID    string `xml:"id,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"identifier"`
+		Titles    []string `xml:"title"`
+		Languages []string `xml:"language"`
+		Meta      []struct {
+			Property string `xml:"property,attr"`
+			Value    string `xml:",chardata"`
+		} `xml:"meta"`
+	} `xml:"metadata"`
+	Items []struct {
+		ID         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		MediaType  string `xml:"media-type,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+	Itemrefs []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// validateEPUB checks the structure the EPUB 3 specification requires of book and returns its files.
+func validateEPUB(tb testing.TB, book []byte) map[string]string {
+	tb.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(book), int64(len(book)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for i, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			tb.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			tb.Fatal(err)
+		}
+		files[f.Name] = string(data)
+
+		if i == 0 && (f.Name != "mimetype" || f.Method != zip.Store || string(data) != "application/epub+zip") {
+			tb.Fatalf("the first file must be an uncompressed mimetype, got %s (method %d): %q", f.Name, f.Method, data)
+		}
+		if strings.HasSuffix(f.Name, ".xml") || strings.HasSuffix(f.Name, ".opf") || strings.HasSuffix(f.Name, ".xhtml") {
+			mustBeWellFormed(tb, f.Name, data)
+		}
+	}
+
+	var container epubContainer
+	if err = xml.Unmarshal([]byte(files["META-INF/container.xml"]), &container); err != nil {
+		tb.Fatal(err)
+	}
+	if len(container.Rootfiles) != 1 || container.Rootfiles[0].MediaType != "application/oebps-package+xml" {
+		tb.Fatalf("container.xml must point at one package document: %+v", container)
+	}
+	opfPath := container.Rootfiles[0].FullPath
+
+	var pkg epubPackage
+	if err = xml.Unmarshal([]byte(files[opfPath]), &pkg); err != nil {
+		tb.Fatal(err)
+	}
+	if pkg.Version != "3.0" {
+		tb.Errorf("package version must be 3.0, got %q", pkg.Version)
+	}
+	if len(pkg.Metadata.Identifiers) == 0 || pkg.Metadata.Identifiers[0].ID != pkg.UniqueIdentifier || pkg.Metadata.Identifiers[0].Value == "" {
+		tb.Errorf("package must have an identifier matching unique-identifier %q", pkg.UniqueIdentifier)
+	}
+	if len(pkg.Metadata.Titles) == 0 || len(pkg.Metadata.Languages) == 0 {
+		tb.Errorf("package must have a title and language")
+	}
+	modified := false
+	for _, m := range pkg.Metadata.Meta {
+		if m.Property == "dcterms:modified" {
+			_, perr := time.Parse("2006-01-02T15:04:05Z", m.Value)
+			modified = perr == nil
+		}
+	}
+	if !modified {
+		tb.Errorf("package must have a dcterms:modified timestamp")
+	}
+
+	base := path.Dir(opfPath)
+	ids := make(map[string]string)
+	navs := 0
+	for _, item := range pkg.Items {
+		target := path.Join(base, item.Href)
+		if _, ok := files[target]; !ok {
+			tb.Errorf("manifest item %s refers to missing file %s", item.ID, target)
+		}
+		if strings.Contains(item.Properties, "nav") {
+			navs++
+		}
+		ids[item.ID] = target
+	}
+	if navs != 1 {
+		tb.Errorf("manifest must have exactly one nav document, got %d", navs)
+	}
+	for name := range files {
+		listed := name == "mimetype" || name == opfPath || strings.HasPrefix(name, "META-INF/")
+		for _, target := range ids {
+			listed = listed || target == name
+		}
+		if !listed {
+			tb.Errorf("%s is not in the manifest", name)
+		}
+	}
+	if len(pkg.Itemrefs) == 0 {
+		tb.Errorf("spine is empty")
+	}
+	for _, ref := range pkg.Itemrefs {
+		if _, ok := ids[ref.IDRef]; !ok {
+			tb.Errorf("spine refers to unknown item %s", ref.IDRef)
+		}
+	}
+
+	// every link in the table of contents must resolve to a document and fragment in the book.
+	nav := files[path.Join(base, "nav.xhtml")]
+	if !strings.Contains(nav, `epub:type="toc"`) {
+		tb.Errorf("nav.xhtml has no toc")
+	}
+	for _, href := range xmlAttrs(tb, nav, "href") {
+		doc, fragment, _ := strings.Cut(href, "#")
+		target, ok := files[path.Join(base, doc)]
+		if !ok {
+			tb.Errorf("toc links to missing document %s", href)
+			continue
+		}
+		if fragment != "" && !strings.Contains(target, `id="`+fragment+`"`) {
+			tb.Errorf("toc links to missing fragment %s", href)
+		}
+	}
+
+	return files
+}
+
+func mustBeWellFormed(tb testing.TB, name string, data []byte) {
+	tb.Helper()
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		_, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			tb.Fatalf("%s is not well-formed XML: %v\n%s", name, err, data)
+		}
+	}
+}
+
+// xmlAttrs returns the values of every attribute named name in doc.
+func xmlAttrs(tb testing.TB, doc, name string) []string {
+	tb.Helper()
+
+	var values []string
+	dec := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return values
+		}
+		if err != nil {
+			tb.Fatal(err)
+		}
+		if el, ok := tok.(xml.StartElement); ok {
+			for _, a := range el.Attr {
+				if a.Name.Local == name {
+					values = append(values, a.Value)
+				}
+			}
+		}
+	}
+}
+
+func epubIdentifier(tb testing.TB, files map[string]string) string {
+	tb.Helper()
+
+	var pkg epubPackage
+	if err := xml.Unmarshal([]byte(files["OEBPS/content.opf"]), &pkg); err != nil {
+		tb.Fatal(err)
+	}
+	return pkg.Metadata.Identifiers[0].Value
+}
@@ -0,0 +1,269 @@
+package jrnl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	exportDateLayout = "2006-01-02"
+	exportIndexName  = ".jrnl-export"
+
+	exportAttachmentDir = "attachments/"
+)
+
+// MarkdownExportOptions configures ExportMarkdown.
+type MarkdownExportOptions struct {
+	// GroupByMonth writes entries into year/month folders, e.g. 2023/01/2023-01-05.md.
+	GroupByMonth bool
+}
+
+// ExportResult describes what an export changed on disk.
+type ExportResult struct {
+	Written   int
+	Unchanged int
+	Removed   int
+}
+
+// ExportMarkdown writes every entry into dir as a markdown file named by its creation date
+// with the entry's metadata in YAML front matter. The first entry of a day is named
+// 2006-01-02.md and any later ones that day get their ID appended. Attachments are written
+// into an attachments folder and the entries link to them there.
+//
+// Exports are deterministic: files whose contents haven't changed are left untouched and
+// files written by a previous export for entries that no longer exist are removed, so
+// re-exporting into the same directory produces a minimal diff. Files not written by an
+// export are never touched.
+func (j *Journal) ExportMarkdown(dir string, opts MarkdownExportOptions) (ExportResult, error) {
+	entries, err := j.ListEntries()
+	if err != nil {
+		return ExportResult{}, err
+	}
+
+	out, err := openExportDir(dir)
+	if err != nil {
+		return ExportResult{}, err
+	}
+
+	// attachments are written into one folder, linked relative to the entries' files.
+	root := ""
+	if opts.GroupByMonth {
+		root = "../../"
+	}
+
+	paths := markdownPaths(entries, opts)
+	for _, e := range entries {
+		e.Content = linkAttachments(e, e.Content, func(a Attachment) string {
+			return root + exportAttachmentDir + a.fileName()
+		})
+		if err = out.write(paths[e.ID], renderMarkdown(e)); err != nil {
+			return out.result, err
+		}
+		if err = j.writeAttachments(out, e); err != nil {
+			return out.result, err
+		}
+	}
+
+	return out.close()
+}
+
+// exportDir writes the files of an export. Files whose contents haven't changed are left
+// untouched, and files a previous export wrote that this one didn't are removed when the
+// export is closed. The files written are listed in an index file inside the directory.
+type exportDir struct {
+	root     string
+	previous []string
+	written  map[string]bool
+	result   ExportResult
+}
+
+func openExportDir(root string) (*exportDir, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+
+	previous, err := readExportIndex(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exportDir{root: root, previous: previous, written: make(map[string]bool)}, nil
+}
+
+// write writes data to rel, a slash separated path relative to the export directory.
+func (d *exportDir) write(rel string, data []byte) error {
+	d.written[rel] = true
+
+	changed, err := writeFileIfChanged(filepath.Join(d.root, filepath.FromSlash(rel)), data)
+	if err != nil {
+		return err
+	}
+	if changed {
+		d.result.Written++
+	} else {
+		d.result.Unchanged++
+	}
+
+	return nil
+}
+
+// close removes stale files and records what this export wrote.
+func (d *exportDir) close() (ExportResult, error) {
+	for _, rel := range d.previous {
+		if d.written[rel] || filepath.IsAbs(rel) || strings.HasPrefix(filepath.Clean(rel), "..") {
+			continue
+		}
+		path := filepath.Join(d.root, filepath.FromSlash(rel))
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return d.result, err
+		}
+		if err == nil {
+			d.result.Removed++
+		}
+		removeEmptyParents(d.root, filepath.Dir(path))
+	}
+
+	index := make([]string, 0, len(d.written))
+	for rel := range d.written {
+		index = append(index, rel)
+	}
+	sort.Strings(index)
+	if _, err := writeFileIfChanged(filepath.Join(d.root, exportIndexName), []byte(strings.Join(index, "\n")+"\n")); err != nil {
+		return d.result, err
+	}
+
+	return d.result, nil
+}
+
+// writeAttachments writes the data of e's attachments into the export's attachments folder.
+// Attachments whose data isn't in the journal are left out.
+func (j *Journal) writeAttachments(out *exportDir, e Entry) error {
+	for _, a := range e.Attachments {
+		data, err := j.ReadAttachment(a.ID)
+		if errors.Is(err, ErrAttachmentNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err = out.write(exportAttachmentDir+a.fileName(), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markdownPaths picks a file path relative to the export directory for every entry.
+func markdownPaths(entries []Entry, opts MarkdownExportOptions) map[int]string {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	paths := make(map[int]string, len(entries))
+	taken := make(map[string]bool, len(entries))
+	for _, e := range sorted {
+		day := e.CreateTime.Format(exportDateLayout)
+		name := day + ".md"
+		if taken[name] {
+			name = fmt.Sprintf("%s-%d.md", day, e.ID)
+		}
+		taken[name] = true
+
+		if opts.GroupByMonth {
+			name = e.CreateTime.Format("2006/01/") + name
+		}
+		paths[e.ID] = name
+	}
+
+	return paths
+}
+
+// renderMarkdown renders an entry as markdown with YAML front matter.
+func renderMarkdown(e Entry) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("---\n")
+	fmt.Fprintf(&buf, "id: %d\n", e.ID)
+	fmt.Fprintf(&buf, "created: %s\n", e.CreateTime.Format(time.RFC3339))
+	fmt.Fprintf(&buf, "updated: %s\n", e.UpdateTime.Format(time.RFC3339))
+	if e.Title != "" {
+		fmt.Fprintf(&buf, "title: %s\n", strconv.Quote(e.Title))
+	}
+	if e.Starred {
+		buf.WriteString("starred: true\n")
+	}
+	if len(e.Tags) > 0 {
+		tags := make([]string, 0, len(e.Tags))
+		for _, t := range e.Tags {
+			tags = append(tags, strconv.Quote(t))
+		}
+		fmt.Fprintf(&buf, "tags: [%s]\n", strings.Join(tags, ", "))
+	}
+	buf.WriteString("---\n\n")
+
+	buf.WriteString(e.Content)
+	if !strings.HasSuffix(e.Content, "\n") {
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
+
+// writeFileIfChanged writes data to path unless the file already holds exactly data.
+func writeFileIfChanged(path string, data []byte) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, data) {
+		return false, nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return false, err
+	}
+
+	return true, os.WriteFile(path, data, 0600)
+}
+
+func readExportIndex(dir string) ([]string, error) {
+	f, err := os.Open(filepath.Join(dir, exportIndexName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			paths = append(paths, line)
+		}
+	}
+
+	return paths, scanner.Err()
+}
+
+// removeEmptyParents removes dir and its parents up to, but not including, root while they are empty.
+func removeEmptyParents(root, dir string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package jrnl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestJournal_ExportMarkdown(t *testing.T) {
+	f, closeFunc := mustNewTestFile(t)
+	t.Cleanup(closeFunc)
+
+	j, jCloseFunc := mustNewAuthenticatedJournal(t, f.Name())
+	t.Cleanup(jCloseFunc)
+
+	day := time.Date(2023, time.January, 5, 9, 30, 0, 0, time.UTC)
+	mustPutEntry(t, j, Entry{ID: 1, Content: "morning pages", CreateTime: day, UpdateTime: day})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "evening thoughts\n", CreateTime: day.Add(10 * time.Hour), UpdateTime: day.Add(11 * time.Hour), Title: `A "long" day`, Starred: true, Tags: []string{"work", "home"}})
+	mustPutEntry(t, j, Entry{ID: 3, Content: "next month", CreateTime: day.AddDate(0, 1, 0), UpdateTime: day.AddDate(0, 1, 0)})
+
+	t.Run("flat", func(t *testing.T) {
+		dir := t.TempDir()
+
+		res, err := j.ExportMarkdown(dir, MarkdownExportOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(res, ExportResult{Written: 3}); diff != "" {
+			t.Errorf("ExportMarkdown() (-got, +want):\n%s", diff)
+		}
+
+		got, err := os.ReadFile(filepath.Join(dir, "2023-01-05-2.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "---\nid: 2\ncreated: 2023-01-05T19:30:00Z\nupdated: 2023-01-05T20:30:00Z\ntitle: \"A \\\"long\\\" day\"\nstarred: true\ntags: [\"work\", \"home\"]\n---\n\nevening thoughts\n"
+		if diff := cmp.Diff(string(got), want); diff != "" {
+			t.Errorf("exported file (-got, +want):\n%s", diff)
+		}
+
+		for _, name := range []string{"2023-01-05.md", "2023-02-05.md"} {
+			if _, err = os.Stat(filepath.Join(dir, name)); err != nil {
+				t.Errorf("expected %s to be exported: %v", name, err)
+			}
+		}
+
+		res, err = j.ExportMarkdown(dir, MarkdownExportOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(res, ExportResult{Unchanged: 3}); diff != "" {
+			t.Errorf("ExportMarkdown() again (-got, +want):\n%s", diff)
+		}
+	})
+
+	t.Run("grouped by month", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("mine"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := j.ExportMarkdown(dir, MarkdownExportOptions{GroupByMonth: true}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "2023", "02", "2023-02-05.md")); err != nil {
+			t.Errorf("expected entry in year/month folder: %v", err)
+		}
+
+		if err := j.DeleteEntry(3); err != nil {
+			t.Fatal(err)
+		}
+		res, err := j.ExportMarkdown(dir, MarkdownExportOptions{GroupByMonth: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(res, ExportResult{Unchanged: 2, Removed: 1}); diff != "" {
+			t.Errorf("ExportMarkdown() after delete (-got, +want):\n%s", diff)
+		}
+		if _, err = os.Stat(filepath.Join(dir, "2023", "02")); !os.IsNotExist(err) {
+			t.Errorf("expected empty month folder to be removed, got %v", err)
+		}
+		if _, err = os.Stat(filepath.Join(dir, "notes.md")); err != nil {
+			t.Errorf("export removed a file it didn't write: %v", err)
+		}
+	})
+}
+
+// mustPutEntry stores e as is, bypassing the timestamps CreateEntry assigns.
+func mustPutEntry(tb testing.TB, j *Journal, e Entry) {
+	tb.Helper()
+
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(journalBucketName))
+		if err := b.SetSequence(uint64(e.ID)); err != nil {
+			return err
+		}
+		return j.putEntry(b, e)
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+}
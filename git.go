@@ -0,0 +1,524 @@
+package jrnl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	gitEntriesDir   = "entries"
+	gitEntryExt     = ".jrnl"
+	gitDefaultRef   = "main"
+	gitRemoteName   = "origin"
+	gitAttributes   = "*.jrnl binary\n"
+	gitFallbackName = "jrnl"
+	gitFallbackMail = "jrnl@localhost"
+)
+
+// ErrGitNotConfigured is returned when syncing a journal that isn't kept in a git repository.
+var ErrGitNotConfigured = errors.New("git history is not configured")
+
+// GitConfig configures a git repository that journal history is kept in.
+type GitConfig struct {
+	// Dir is the working tree of the repository. It is created if it doesn't exist.
+	Dir string `json:"dir"`
+	// Remote is any URL git understands, including the path to a local bare repository.
+	Remote string `json:"remote"`
+	Branch string `json:"branch"`
+}
+
+// GitRepo is a git working tree holding one encrypted file per journal entry.
+// It shells out to the git binary so it behaves exactly like the user's own tooling.
+type GitRepo struct {
+	dir    string
+	remote string
+	branch string
+	env    []string
+}
+
+// OpenGitRepo opens the repository described by cfg, initializing it if needed.
+func OpenGitRepo(cfg GitConfig) (*GitRepo, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("git dir is required")
+	}
+	if cfg.Branch == "" {
+		cfg.Branch = gitDefaultRef
+	}
+
+	r := &GitRepo{
+		dir:    cfg.Dir,
+		remote: cfg.Remote,
+		branch: cfg.Branch,
+		env:    append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_EDITOR=true"),
+	}
+
+	if err := os.MkdirAll(filepath.Join(r.dir, gitEntriesDir), 0700); err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(filepath.Join(r.dir, ".git")); errors.Is(err, os.ErrNotExist) {
+		if _, err = r.git("init", "--quiet"); err != nil {
+			return nil, err
+		}
+		if _, err = r.git("symbolic-ref", "HEAD", "refs/heads/"+r.branch); err != nil {
+			return nil, err
+		}
+	}
+
+	// fall back to a neutral identity so commits work on machines without a git config.
+	if out, _ := r.git("config", "user.email"); strings.TrimSpace(out) == "" {
+		r.env = append(r.env,
+			"GIT_AUTHOR_NAME="+gitFallbackName, "GIT_AUTHOR_EMAIL="+gitFallbackMail,
+			"GIT_COMMITTER_NAME="+gitFallbackName, "GIT_COMMITTER_EMAIL="+gitFallbackMail,
+		)
+	}
+
+	if err := os.WriteFile(filepath.Join(r.dir, ".gitattributes"), []byte(gitAttributes), 0600); err != nil {
+		return nil, err
+	}
+	if err := r.commit("Initialize journal", ".gitattributes"); err != nil {
+		return nil, err
+	}
+
+	if r.remote != "" {
+		out, _ := r.git("remote", "get-url", gitRemoteName)
+		switch strings.TrimSpace(out) {
+		case r.remote:
+		case "":
+			if _, err := r.git("remote", "add", gitRemoteName, r.remote); err != nil {
+				return nil, err
+			}
+		default:
+			if _, err := r.git("remote", "set-url", gitRemoteName, r.remote); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// EnableGitHistory keeps the journal in repo. Every create, edit and delete becomes
+// a commit of the encrypted entry files, and entries that predate the repository are
+// committed straight away.
+func (j *Journal) EnableGitHistory(repo *GitRepo) error {
+	j.git = repo
+
+	_, err := j.reconcileGit()
+	return err
+}
+
+// GitEnabled tells us if the journal is kept in a git repository.
+func (j *Journal) GitEnabled() bool {
+	return j.git != nil
+}
+
+// SyncGit rebases local history onto the remote, applies the remote's changes to the
+// journal and pushes the result. Entries edited on both sides keep the local version and
+// the remote version is stored as a new entry, which is reported as a Conflict.
+func (j *Journal) SyncGit(ctx context.Context) (SyncResult, error) {
+	if j.git == nil {
+		return SyncResult{}, ErrGitNotConfigured
+	}
+	if j.git.remote == "" {
+		return SyncResult{}, fmt.Errorf("git remote is not configured")
+	}
+
+	// make sure anything the database has that git doesn't is committed before rebasing.
+	if _, err := j.reconcileGit(); err != nil {
+		return SyncResult{}, err
+	}
+
+	if _, err := j.git.gitContext(ctx, "fetch", "--quiet", gitRemoteName); err != nil {
+		return SyncResult{}, err
+	}
+
+	upstream := gitRemoteName + "/" + j.git.branch
+	var conflicts []string
+	if _, err := j.git.gitContext(ctx, "rev-parse", "--verify", "--quiet", upstream); err == nil {
+		conflicts, err = j.rebaseGit(ctx, upstream)
+		if err != nil {
+			return SyncResult{}, err
+		}
+	}
+
+	result, err := j.reconcileGit()
+	if err != nil {
+		return result, err
+	}
+
+	if _, err = j.git.gitContext(ctx, "push", "--quiet", gitRemoteName, "HEAD:refs/heads/"+j.git.branch); err != nil {
+		return result, err
+	}
+
+	if len(conflicts) == 0 {
+		return result, nil
+	}
+
+	err = j.db.View(func(tx *bolt.Tx) error {
+		byFile := gitEntryIDs(tx.Bucket([]byte(gitBucketName)))
+		for _, pair := range conflicts {
+			parts := strings.SplitN(pair, ":", 2)
+			result.Conflicts = append(result.Conflicts, Conflict{
+				EntryID: byFile[parts[0]],
+				CopyID:  byFile[parts[1]],
+			})
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// rebaseGit replays local commits onto upstream. Conflicting entry files keep the local
+// version while the upstream version is written to a new file. It returns the conflicts
+// as "original:copy" file ID pairs.
+func (j *Journal) rebaseGit(ctx context.Context, upstream string) ([]string, error) {
+	var conflicts []string
+
+	_, err := j.git.gitContext(ctx, "rebase", "--quiet", upstream)
+	for err != nil {
+		out, derr := j.git.gitContext(ctx, "diff", "--name-only", "--diff-filter=U")
+		if derr != nil {
+			return nil, derr
+		}
+		paths := strings.Fields(out)
+		if len(paths) == 0 {
+			_, _ = j.git.gitContext(ctx, "rebase", "--abort")
+			return nil, fmt.Errorf("rebasing onto %s: %w", upstream, err)
+		}
+
+		for _, path := range paths {
+			copyID, rerr := j.resolveGitConflict(ctx, path)
+			if rerr != nil {
+				_, _ = j.git.gitContext(ctx, "rebase", "--abort")
+				return nil, rerr
+			}
+			if copyID != "" {
+				conflicts = append(conflicts, fileID(path)+":"+copyID)
+			}
+		}
+
+		_, err = j.git.gitContext(ctx, "rebase", "--continue")
+	}
+
+	return conflicts, nil
+}
+
+// resolveGitConflict resolves a conflicted entry file mid-rebase. During a rebase stage 2
+// holds the upstream version and stage 3 the local commit being replayed.
+func (j *Journal) resolveGitConflict(ctx context.Context, path string) (string, error) {
+	upstream, upErr := j.git.gitContext(ctx, "show", ":2:"+path)
+	local, localErr := j.git.gitContext(ctx, "show", ":3:"+path)
+	full := filepath.Join(j.git.dir, path)
+
+	switch {
+	case localErr != nil && upErr != nil:
+		_, err := j.git.gitContext(ctx, "rm", "--quiet", "--", path)
+		return "", err
+	case localErr != nil:
+		// deleted locally but edited upstream, keep the edit.
+		if err := os.WriteFile(full, []byte(upstream), 0600); err != nil {
+			return "", err
+		}
+		_, err := j.git.gitContext(ctx, "add", "--", path)
+		return "", err
+	case upErr != nil:
+		// deleted upstream but edited locally, keep the edit.
+		if err := os.WriteFile(full, []byte(local), 0600); err != nil {
+			return "", err
+		}
+		_, err := j.git.gitContext(ctx, "add", "--", path)
+		return "", err
+	}
+
+	e, err := j.decodeEntry([]byte(upstream))
+	if err != nil {
+		return "", err
+	}
+	e.Content = conflictNotice(e.UpdateTime) + e.Content
+	data, err := j.encodeEntry(e)
+	if err != nil {
+		return "", err
+	}
+
+	copyID, err := randomID()
+	if err != nil {
+		return "", err
+	}
+	copyPath := gitEntryPath(copyID)
+	if err = os.WriteFile(filepath.Join(j.git.dir, copyPath), data, 0600); err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(full, []byte(local), 0600); err != nil {
+		return "", err
+	}
+	if _, err = j.git.gitContext(ctx, "add", "--", path, copyPath); err != nil {
+		return "", err
+	}
+
+	return copyID, nil
+}
+
+// reconcileGit makes the database and the working tree agree. Files without a local entry
+// are imported, local entries whose file disappeared are deleted and local entries without
+// a file are written out and committed. Files of entries deleted locally whose removal wasn't
+// committed, because removing or committing it failed, are removed now.
+func (j *Journal) reconcileGit() (SyncResult, error) {
+	var (
+		result  SyncResult
+		written []string
+		removed []string
+	)
+
+	names, err := os.ReadDir(filepath.Join(j.git.dir, gitEntriesDir))
+	if errors.Is(err, os.ErrNotExist) {
+		// git doesn't keep empty directories, so pulling the delete of the last entry removes it.
+		err = os.MkdirAll(filepath.Join(j.git.dir, gitEntriesDir), 0700)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	err = j.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket([]byte(journalBucketName))
+		ids := tx.Bucket([]byte(gitBucketName))
+		byFile := gitEntryIDs(ids)
+
+		files := make(map[string]bool)
+		for _, name := range names {
+			if name.IsDir() || filepath.Ext(name.Name()) != gitEntryExt {
+				continue
+			}
+			fid := strings.TrimSuffix(name.Name(), gitEntryExt)
+			files[fid] = true
+
+			data, err := os.ReadFile(filepath.Join(j.git.dir, gitEntryPath(fid)))
+			if err != nil {
+				return err
+			}
+			remote, err := j.decodeEntry(data)
+			if err != nil {
+				return fmt.Errorf("decrypting %s: %w", name.Name(), err)
+			}
+
+			if localID, ok := byFile[fid]; ok {
+				local, err := j.getEntry(entries, localID)
+				if errors.Is(err, ErrEntryNotFound) {
+					// the entry was deleted locally but removing its file failed, finish the delete.
+					if err = os.Remove(filepath.Join(j.git.dir, gitEntryPath(fid))); err != nil {
+						return err
+					}
+					removed = append(removed, gitEntryPath(fid))
+					result.Pushed++
+					if err = ids.Delete(itob(localID)); err != nil {
+						return err
+					}
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if hashEntry(local) == hashEntry(remote) {
+					continue
+				}
+				if local.UpdateTime.After(remote.UpdateTime) {
+					// the database is ahead of git, e.g. a commit failed after an edit.
+					if err = os.WriteFile(filepath.Join(j.git.dir, gitEntryPath(fid)), entries.Get(itob(localID)), 0600); err != nil {
+						return err
+					}
+					written = append(written, gitEntryPath(fid))
+					result.Pushed++
+					continue
+				}
+				remote.ID = localID
+			} else {
+				seq, err := entries.NextSequence()
+				if err != nil {
+					return err
+				}
+				remote.ID = int(seq)
+			}
+
+			if err = j.putEntry(entries, remote); err != nil {
+				return err
+			}
+			if err = ids.Put(itob(remote.ID), []byte(fid)); err != nil {
+				return err
+			}
+			result.Pulled++
+		}
+
+		for fid, localID := range byFile {
+			if files[fid] {
+				continue
+			}
+			if entries.Get(itob(localID)) == nil {
+				// the entry's file was removed locally but the delete wasn't committed.
+				removed = append(removed, gitEntryPath(fid))
+				result.Pushed++
+				if err := ids.Delete(itob(localID)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := j.deleteEntry(entries, localID); err != nil {
+				return err
+			}
+			if err := ids.Delete(itob(localID)); err != nil {
+				return err
+			}
+			result.Deleted++
+		}
+
+		return entries.ForEach(func(k, v []byte) error {
+			if ids.Get(k) != nil {
+				return nil
+			}
+			fid, err := randomID()
+			if err != nil {
+				return err
+			}
+			if err = os.WriteFile(filepath.Join(j.git.dir, gitEntryPath(fid)), v, 0600); err != nil {
+				return err
+			}
+			written = append(written, gitEntryPath(fid))
+			result.Pushed++
+			return ids.Put(k, []byte(fid))
+		})
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if len(removed) > 0 {
+		// the files may never have been committed, which git add would fail on.
+		args := append([]string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--"}, removed...)
+		if _, err = j.git.git(args...); err != nil {
+			return result, err
+		}
+	}
+	if len(written)+len(removed) > 0 {
+		err = j.git.commit(fmt.Sprintf("Update %d entries", len(written)+len(removed)), written...)
+	}
+
+	return result, err
+}
+
+// recordGitHistory commits the current state of an entry after it was created, edited or deleted.
+func (j *Journal) recordGitHistory(action string, id int) error {
+	if j.git == nil {
+		return nil
+	}
+
+	var (
+		fid  string
+		data []byte
+	)
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		ids := tx.Bucket([]byte(gitBucketName))
+		data = tx.Bucket([]byte(journalBucketName)).Get(itob(id))
+
+		if v := ids.Get(itob(id)); v != nil {
+			fid = string(v)
+		}
+		if data == nil {
+			// the mapping is kept until the file is gone, so a failed delete is finished by reconcileGit.
+			return nil
+		}
+		if fid == "" {
+			var err error
+			if fid, err = randomID(); err != nil {
+				return err
+			}
+		}
+		// copy the ciphertext out, bolt memory is only valid for the life of the transaction.
+		data = append([]byte(nil), data...)
+
+		return ids.Put(itob(id), []byte(fid))
+	})
+	if err != nil || fid == "" {
+		return err
+	}
+
+	path := gitEntryPath(fid)
+	if data == nil {
+		if err = os.Remove(filepath.Join(j.git.dir, path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	} else if err = os.WriteFile(filepath.Join(j.git.dir, path), data, 0600); err != nil {
+		return err
+	}
+
+	// commit messages only ever reference the opaque file ID, never entry content.
+	if err = j.git.commit(fmt.Sprintf("%s entry %s", action, fid), path); err != nil || data != nil {
+		return err
+	}
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(gitBucketName)).Delete(itob(id))
+	})
+}
+
+// commit stages paths and commits them, along with anything already staged, if anything changed.
+func (r *GitRepo) commit(message string, paths ...string) error {
+	if len(paths) > 0 {
+		args := append([]string{"add", "--all", "--"}, paths...)
+		if _, err := r.git(args...); err != nil {
+			return err
+		}
+	}
+
+	// diff --cached exits non-zero when there is something staged.
+	if _, err := r.git("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+
+	_, err := r.git("commit", "--quiet", "--no-verify", "-m", message)
+	return err
+}
+
+func (r *GitRepo) git(args ...string) (string, error) {
+	return r.gitContext(context.Background(), args...)
+}
+
+func (r *GitRepo) gitContext(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = r.env
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// gitEntryIDs maps entry file IDs to local entry IDs.
+func gitEntryIDs(b *bolt.Bucket) map[string]int {
+	byFile := make(map[string]int)
+	_ = b.ForEach(func(k, v []byte) error {
+		byFile[string(v)] = btoi(k)
+		return nil
+	})
+	return byFile
+}
+
+func gitEntryPath(fid string) string {
+	return gitEntriesDir + "/" + fid + gitEntryExt
+}
+
+func fileID(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), gitEntryExt)
+}
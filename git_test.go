@@ -0,0 +1,221 @@
+package jrnl
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestJournal_GitHistory(t *testing.T) {
+	remote := mustNewBareRepo(t)
+	j, repo := mustNewGitJournal(t, remote)
+
+	e := mustCreateEntry(t, j, "dear diary, today was a secret")
+	if _, err := j.EditEntry(e.ID, "dear diary, today was still a secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.DeleteEntry(e.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := repo.git("log", "--format=%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := strings.Split(strings.TrimSpace(log), "\n")
+	if len(messages) != 4 {
+		t.Fatalf("got commits %q, want an init commit plus one per change", messages)
+	}
+	for i, prefix := range []string{"Delete entry", "Edit entry", "Create entry"} {
+		if !strings.HasPrefix(messages[i], prefix) {
+			t.Errorf("commit %d = %q, want prefix %q", i, messages[i], prefix)
+		}
+	}
+
+	history, err := repo.git("log", "--all", "-p", "--text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(history, "diary") || strings.Contains(history, "secret") {
+		t.Errorf("git history contains plaintext")
+	}
+}
+
+func TestJournal_SyncGit(t *testing.T) {
+	t.Run("changes propagate between devices", func(t *testing.T) {
+		remote := mustNewBareRepo(t)
+		laptop, _ := mustNewGitJournal(t, remote)
+		desktop, _ := mustNewGitJournal(t, remote)
+
+		kept := mustCreateEntry(t, laptop, "keep me")
+		removed := mustCreateEntry(t, laptop, "delete me")
+		mustSyncGit(t, laptop)
+
+		res := mustSyncGit(t, desktop)
+		if res.Pulled != 2 {
+			t.Errorf("SyncGit() pulled = %d, want 2", res.Pulled)
+		}
+
+		if _, err := laptop.EditEntry(kept.ID, "keep me, edited"); err != nil {
+			t.Fatal(err)
+		}
+		if err := laptop.DeleteEntry(removed.ID); err != nil {
+			t.Fatal(err)
+		}
+		mustSyncGit(t, laptop)
+
+		res = mustSyncGit(t, desktop)
+		if res.Pulled != 1 || res.Deleted != 1 {
+			t.Errorf("SyncGit() = %+v, want 1 pulled and 1 deleted", res)
+		}
+
+		entries, err := desktop.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Content != "keep me, edited" {
+			t.Errorf("ListEntries() = %+v, want only the edited entry", entries)
+		}
+	})
+
+	t.Run("concurrent edits keep both versions", func(t *testing.T) {
+		remote := mustNewBareRepo(t)
+		laptop, _ := mustNewGitJournal(t, remote)
+		desktop, _ := mustNewGitJournal(t, remote)
+
+		e := mustCreateEntry(t, laptop, "original")
+		mustSyncGit(t, laptop)
+		mustSyncGit(t, desktop)
+
+		desktopEntries, err := desktop.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = laptop.EditEntry(e.ID, "laptop edit"); err != nil {
+			t.Fatal(err)
+		}
+		mustSyncGit(t, laptop)
+
+		if _, err = desktop.EditEntry(desktopEntries[0].ID, "desktop edit"); err != nil {
+			t.Fatal(err)
+		}
+		res := mustSyncGit(t, desktop)
+		if len(res.Conflicts) != 1 {
+			t.Fatalf("SyncGit() conflicts = %+v, want 1", res.Conflicts)
+		}
+
+		mustSyncGit(t, laptop)
+		for _, j := range []*Journal{laptop, desktop} {
+			entries, err := j.ListEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sawDesktop, sawLaptop bool
+			for _, entry := range entries {
+				sawDesktop = sawDesktop || entry.Content == "desktop edit"
+				sawLaptop = sawLaptop || strings.HasSuffix(entry.Content, "laptop edit")
+			}
+			if len(entries) != 2 || !sawDesktop || !sawLaptop {
+				t.Errorf("ListEntries() = %+v, want both edits", entries)
+			}
+		}
+	})
+
+	t.Run("an interrupted delete is finished by the next sync", func(t *testing.T) {
+		remote := mustNewBareRepo(t)
+		laptop, _ := mustNewGitJournal(t, remote)
+		desktop, _ := mustNewGitJournal(t, remote)
+
+		e := mustCreateEntry(t, laptop, "delete me")
+		mustSyncGit(t, laptop)
+		mustSyncGit(t, desktop)
+
+		// delete the entry from the database only, as if removing its file had failed.
+		err := laptop.db.Update(func(tx *bolt.Tx) error {
+			return laptop.deleteEntry(tx.Bucket([]byte(journalBucketName)), e.ID)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mustSyncGit(t, laptop)
+
+		if res := mustSyncGit(t, desktop); res.Deleted != 1 {
+			t.Errorf("SyncGit() = %+v, want 1 deleted", res)
+		}
+		entries, err := laptop.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("ListEntries() = %+v, want the entry to stay deleted", entries)
+		}
+	})
+
+	t.Run("sync without a repository", func(t *testing.T) {
+		f, closeFunc := mustNewTestFile(t)
+		t.Cleanup(closeFunc)
+
+		j, jCloseFunc := mustNewAuthenticatedJournal(t, f.Name())
+		t.Cleanup(jCloseFunc)
+
+		if _, err := j.SyncGit(context.Background()); err != ErrGitNotConfigured {
+			t.Errorf("SyncGit() error = %v, want %v", err, ErrGitNotConfigured)
+		}
+	})
+}
+
+func mustSyncGit(tb testing.TB, j *Journal) SyncResult {
+	tb.Helper()
+
+	res, err := j.SyncGit(context.Background())
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return res
+}
+
+func mustNewGitJournal(tb testing.TB, remote string) (*Journal, *GitRepo) {
+	tb.Helper()
+
+	f, closeFunc := mustNewTestFile(tb)
+	tb.Cleanup(closeFunc)
+
+	j, jCloseFunc := mustNewAuthenticatedJournal(tb, f.Name())
+	tb.Cleanup(jCloseFunc)
+
+	repo, err := OpenGitRepo(GitConfig{Dir: filepath.Join(tb.TempDir(), "journal"), Remote: remote})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err = j.EnableGitHistory(repo); err != nil {
+		tb.Fatal(err)
+	}
+
+	return j, repo
+}
+
+func mustNewBareRepo(tb testing.TB) string {
+	tb.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		tb.Skip("git is not installed")
+	}
+
+	dir := filepath.Join(tb.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", dir).CombinedOutput(); err != nil {
+		tb.Fatalf("git init: %v: %s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "HEAD"), []byte("ref: refs/heads/main\n"), 0600); err != nil {
+		tb.Fatal(err)
+	}
+
+	return dir
+}
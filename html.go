@@ -0,0 +1,292 @@
+package jrnl
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+const (
+	siteTimeLayout = "Mon, 02 Jan 2006 3:04PM MST"
+	siteExcerptLen = 160
+)
+
+//go:embed site
+var siteFS embed.FS
+
+var siteTemplates = template.Must(template.ParseFS(siteFS, "site/site.tmpl"))
+
+type siteEntry struct {
+	ID      int
+	Title   string
+	Date    string
+	URL     string
+	Excerpt string
+	Text    string
+	html    string
+	Tags    []*siteTag
+	Prev    *siteEntry
+	Next    *siteEntry
+}
+
+// HTMLAt returns the entry's rendered content for a page root away from the top of the site.
+func (e *siteEntry) HTMLAt(root string) template.HTML {
+	// goldmark escapes raw HTML in entries unless it is explicitly told not to.
+	return template.HTML(strings.ReplaceAll(e.html, `="`+attachmentScheme, `="`+root+exportAttachmentDir))
+}
+
+type siteTag struct {
+	Name    string
+	URL     string
+	Entries []*siteEntry
+}
+
+type siteMonth struct {
+	Name    string
+	URL     string
+	Count   int
+	Entries []*siteEntry
+}
+
+type siteYear struct {
+	Year   int
+	URL    string
+	Count  int
+	Months []*siteMonth
+}
+
+// sitePageFile is a page of the site and the template that renders it.
+type sitePageFile struct {
+	path string
+	name string
+	page sitePage
+}
+
+type sitePage struct {
+	Root  string
+	Title string
+	List  []*siteEntry
+	Years []*siteYear
+	Year  *siteYear
+	Month *siteMonth
+	Entry *siteEntry
+	Tags  []*siteTag
+	Tag   *siteTag
+}
+
+// WithList returns a copy of the page listing entries, for use by the entryList template.
+func (p sitePage) WithList(entries []*siteEntry) sitePage {
+	p.List = entries
+	return p
+}
+
+type searchItem struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// ExportHTML renders the journal into dir as a self-contained static site: a chronological
+// index with client-side search, year and month archive pages, a page per tag and a page per entry.
+// Markdown is rendered with goldmark and every asset is written alongside the pages, so the
+// site works offline when opened straight from disk in any browser.
+//
+// Like ExportMarkdown, re-exporting into the same directory only touches pages that changed.
+func (j *Journal) ExportHTML(dir string) (ExportResult, error) {
+	entries, err := j.ListEntries()
+	if err != nil {
+		return ExportResult{}, err
+	}
+
+	out, err := openExportDir(dir)
+	if err != nil {
+		return ExportResult{}, err
+	}
+
+	// every listing is newest first by creation time, imported entries can be older than their IDs suggest.
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].CreateTime.After(entries[b].CreateTime)
+	})
+
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	site := make([]*siteEntry, 0, len(entries))
+	for _, e := range entries {
+		// attachments keep their scheme until HTMLAt knows where the page is relative to them.
+		content := linkAttachments(e, e.Markdown(), func(a Attachment) string {
+			return attachmentScheme + a.fileName()
+		})
+		var buf bytes.Buffer
+		if err = md.Convert([]byte(content), &buf); err != nil {
+			return out.result, err
+		}
+		if err = j.writeAttachments(out, e); err != nil {
+			return out.result, err
+		}
+
+		site = append(site, &siteEntry{
+			ID:      e.ID,
+			Title:   e.CreateTime.Format(siteTimeLayout),
+			Date:    e.CreateTime.Format(time.RFC3339),
+			URL:     fmt.Sprintf("entries/%d.html", e.ID),
+			Excerpt: excerpt(e.Title+"\n"+e.Content, siteExcerptLen),
+			Text:    e.Title + "\n" + e.Content,
+			html:    buf.String(),
+		})
+	}
+	for i, e := range site {
+		if i > 0 {
+			e.Next = site[i-1]
+		}
+		if i < len(site)-1 {
+			e.Prev = site[i+1]
+		}
+	}
+
+	years := groupSiteEntries(entries, site)
+	tags := tagSiteEntries(entries, site)
+
+	pages := []sitePageFile{
+		{"index.html", "index", sitePage{Title: "Journal", List: site}},
+		{"archive/index.html", "archive", sitePage{Root: "../", Title: "Archive", Years: years}},
+		{"tags/index.html", "tags", sitePage{Root: "../", Title: "Tags", Tags: tags}},
+	}
+	for _, t := range tags {
+		pages = append(pages, sitePageFile{t.URL, "tag", sitePage{Root: "../", Title: "#" + t.Name, List: t.Entries, Tag: t}})
+	}
+	for _, y := range years {
+		pages = append(pages, sitePageFile{y.URL, "year", sitePage{Root: "../../", Title: fmt.Sprint(y.Year), Year: y}})
+		for _, m := range y.Months {
+			pages = append(pages, sitePageFile{m.URL, "month", sitePage{Root: "../../../", Title: m.Name, Month: m}})
+		}
+	}
+	for _, e := range site {
+		pages = append(pages, sitePageFile{e.URL, "entry", sitePage{Root: "../", Title: e.Title, Entry: e}})
+	}
+
+	for _, p := range pages {
+		var buf bytes.Buffer
+		if err = siteTemplates.ExecuteTemplate(&buf, p.name, p.page); err != nil {
+			return out.result, err
+		}
+		if err = out.write(p.path, buf.Bytes()); err != nil {
+			return out.result, err
+		}
+	}
+
+	index := make([]searchItem, 0, len(site))
+	for _, e := range site {
+		index = append(index, searchItem{URL: e.URL, Title: e.Title, Text: e.Text})
+	}
+	buf, err := json.Marshal(index)
+	if err != nil {
+		return out.result, err
+	}
+	// the index is loaded with a script tag rather than fetched, browsers block fetch on file:// urls.
+	if err = out.write("assets/search-index.js", []byte("window.jrnlSearchIndex = "+string(buf)+";\n")); err != nil {
+		return out.result, err
+	}
+
+	for _, asset := range []string{"style.css", "search.js"} {
+		data, err := siteFS.ReadFile("site/" + asset)
+		if err != nil {
+			return out.result, err
+		}
+		if err = out.write("assets/"+asset, data); err != nil {
+			return out.result, err
+		}
+	}
+
+	return out.close()
+}
+
+// groupSiteEntries groups entries, which are sorted newest first, into years and months.
+func groupSiteEntries(entries []Entry, site []*siteEntry) []*siteYear {
+	var years []*siteYear
+	for i, e := range entries {
+		t := e.CreateTime
+		if len(years) == 0 || years[len(years)-1].Year != t.Year() {
+			years = append(years, &siteYear{Year: t.Year(), URL: t.Format("archive/2006/index.html")})
+		}
+		y := years[len(years)-1]
+		y.Count++
+
+		name := t.Format("January 2006")
+		if len(y.Months) == 0 || y.Months[len(y.Months)-1].Name != name {
+			y.Months = append(y.Months, &siteMonth{Name: name, URL: t.Format("archive/2006/01/index.html")})
+		}
+		m := y.Months[len(y.Months)-1]
+		m.Count++
+		m.Entries = append(m.Entries, site[i])
+	}
+
+	return years
+}
+
+// tagSiteEntries groups entries by tag, ignoring case, and links each entry to its tags.
+// Tags are sorted by name and keep the spelling they were first seen with. Tags whose slugs
+// are the same, like "a b" and "a-b", have a number added to the slug of the later ones so
+// each has a page of its own.
+func tagSiteEntries(entries []Entry, site []*siteEntry) []*siteTag {
+	byKey := make(map[string]*siteTag)
+	slugs := make(map[string]bool)
+	var tags []*siteTag
+	for i, e := range entries {
+		for _, name := range e.Tags {
+			key := strings.ToLower(name)
+			t, ok := byKey[key]
+			if !ok {
+				slug := tagSlug(key)
+				for n := 2; slugs[slug]; n++ {
+					slug = fmt.Sprintf("%s-%d", tagSlug(key), n)
+				}
+				slugs[slug] = true
+				t = &siteTag{Name: name, URL: "tags/" + slug + ".html"}
+				byKey[key] = t
+				tags = append(tags, t)
+			}
+			if n := len(t.Entries); n > 0 && t.Entries[n-1] == site[i] {
+				continue
+			}
+			t.Entries = append(t.Entries, site[i])
+			site[i].Tags = append(site[i].Tags, t)
+		}
+	}
+
+	sort.Slice(tags, func(a, b int) bool {
+		return strings.ToLower(tags[a].Name) < strings.ToLower(tags[b].Name)
+	})
+
+	return tags
+}
+
+// tagSlug makes a lower case tag safe to use as a file name.
+func tagSlug(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, tag)
+}
+
+// excerpt returns the first n runes of content collapsed onto a single line.
+func excerpt(content string, n int) string {
+	s := strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
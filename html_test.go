@@ -0,0 +1,116 @@
+package jrnl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_ExportHTML(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	day := time.Date(2022, time.December, 31, 22, 0, 0, 0, time.UTC)
+	mustPutEntry(t, j, Entry{ID: 1, Content: "# New year's eve\n\nfireworks <script>alert(1)</script>", CreateTime: day, UpdateTime: day})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "first of the *year*", CreateTime: day.Add(4 * time.Hour), UpdateTime: day.Add(4 * time.Hour), Title: "Day one", Tags: []string{"New Year"}})
+
+	dir := t.TempDir()
+	res, err := j.ExportHTML(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, page := range []string{
+		"index.html",
+		"archive/index.html",
+		"archive/2022/index.html",
+		"archive/2022/12/index.html",
+		"archive/2023/01/index.html",
+		"entries/1.html",
+		"entries/2.html",
+		"tags/index.html",
+		"tags/new-year.html",
+		"assets/style.css",
+		"assets/search.js",
+		"assets/search-index.js",
+	} {
+		if _, err = os.Stat(filepath.Join(dir, page)); err != nil {
+			t.Errorf("expected %s to be exported: %v", page, err)
+		}
+	}
+
+	entry := mustReadFile(t, filepath.Join(dir, "entries/1.html"))
+	for _, want := range []string{"<h1>New year's eve</h1>", `href="../assets/style.css"`, `href="../entries/2.html"`} {
+		if !strings.Contains(entry, want) {
+			t.Errorf("entries/1.html missing %q:\n%s", want, entry)
+		}
+	}
+	if strings.Contains(entry, "<script>alert(1)</script>") {
+		t.Errorf("entries/1.html contains raw html from the entry")
+	}
+
+	tagged := mustReadFile(t, filepath.Join(dir, "entries/2.html"))
+	for _, want := range []string{"<h1>Day one</h1>", `href="../tags/new-year.html">#New Year</a>`} {
+		if !strings.Contains(tagged, want) {
+			t.Errorf("entries/2.html missing %q:\n%s", want, tagged)
+		}
+	}
+	if tag := mustReadFile(t, filepath.Join(dir, "tags/new-year.html")); !strings.Contains(tag, "entries/2.html") || strings.Contains(tag, "entries/1.html") {
+		t.Errorf("tags/new-year.html should only list the tagged entry:\n%s", tag)
+	}
+
+	index := mustReadFile(t, filepath.Join(dir, "index.html"))
+	if strings.Index(index, "entries/2.html") > strings.Index(index, "entries/1.html") {
+		t.Errorf("index.html should list the newest entry first")
+	}
+
+	for _, page := range []string{"index.html", "entries/1.html", "archive/2022/12/index.html", "assets/style.css"} {
+		if strings.Contains(mustReadFile(t, filepath.Join(dir, page)), "http") {
+			t.Errorf("%s references an external asset", page)
+		}
+	}
+
+	again, err := j.ExportHTML(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Written != 0 || again.Unchanged != res.Written {
+		t.Errorf("ExportHTML() again = %+v, want every file unchanged", again)
+	}
+}
+
+func mustReadFile(tb testing.TB, path string) string {
+	tb.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestTagSiteEntries(t *testing.T) {
+	entries := []Entry{
+		{ID: 1, Tags: []string{"a b"}},
+		{ID: 2, Tags: []string{"a-b", "A B"}},
+		{ID: 3, Tags: []string{"a-b-2"}},
+	}
+	site := []*siteEntry{{}, {}, {}}
+
+	urls := make(map[string]string)
+	for _, tag := range tagSiteEntries(entries, site) {
+		urls[tag.Name] = tag.URL
+	}
+	want := map[string]string{
+		"a b":   "tags/a-b.html",
+		"a-b":   "tags/a-b-2.html",
+		"a-b-2": "tags/a-b-2-2.html",
+	}
+	if diff := cmp.Diff(urls, want); diff != "" {
+		t.Errorf("tagSiteEntries() URLs (-got, +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,186 @@
+package jrnl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// importBatchSize is how many entries are written per transaction during an import.
+	importBatchSize = 1000
+
+	// attachmentBatchSize is importBatchSize for imports whose entries can carry a lot of attachments.
+	attachmentBatchSize = 50
+)
+
+// errSkipEntry is returned, wrapped, by an import's next function for an entry it can't read.
+// The entry is counted as skipped and the import carries on.
+var errSkipEntry = errors.New("unreadable entry")
+
+// ImportOptions configures an import.
+type ImportOptions struct {
+	// DryRun reads and validates everything but writes nothing.
+	DryRun bool
+	// Preview, if set, is called with every entry the import creates, or would create during a dry run.
+	Preview func(e Entry)
+}
+
+// ImportResult describes what an import did, or would do during a dry run.
+type ImportResult struct {
+	Imported   int
+	Duplicates int
+	Skipped    int
+}
+
+// importEntries writes every entry returned by next into the journal, keeping the entries'
+// timestamps. next returns io.EOF once there are no more entries, or errSkipEntry for one
+// it can't read. An entry's ID is kept when it is free and a new one is assigned otherwise.
+// Entries with the same creation time and content as one already in the journal, or
+// earlier in the import, are duplicates and are skipped.
+//
+// The data of the entries' attachments is read with readAttachment, which may be nil if
+// the entries have none, and written in the same transaction as the entry. The attachments
+// of entries that aren't written, duplicates and every entry of a dry run, are passed to
+// discard instead, if it isn't nil, so data held for them can be dropped.
+//
+// Entries are committed in batches of batchSize, or in a single transaction if batchSize is 0.
+func (j *Journal) importEntries(next func() (Entry, error), readAttachment func(id string) ([]byte, error), discard func(id string), opts ImportOptions, batchSize int) (ImportResult, error) {
+	var result ImportResult
+
+	seen := make(map[string]bool)
+	err := j.forEachEntry(func(e Entry) error {
+		seen[entryFingerprint(e)] = true
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	discardAttachments := func(e Entry) {
+		if discard == nil {
+			return
+		}
+		for _, a := range e.Attachments {
+			discard(a.ID)
+		}
+	}
+
+	var batch []Entry
+	flush := func() error {
+		if opts.DryRun || len(batch) == 0 {
+			for _, e := range batch {
+				discardAttachments(e)
+			}
+			batch = batch[:0]
+			return nil
+		}
+
+		err := j.db.Update(func(tx *bolt.Tx) error {
+			b := tx.Bucket([]byte(journalBucketName))
+			attachments := tx.Bucket([]byte(attachmentBucketName))
+			for _, e := range batch {
+				for _, a := range e.Attachments {
+					data, err := readAttachment(a.ID)
+					if err != nil {
+						return fmt.Errorf("reading attachment %s: %w", a.Name, err)
+					}
+					if err = j.putAttachment(attachments, a.ID, data); err != nil {
+						return err
+					}
+				}
+				if err := j.insertEntry(b, e); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		batch = batch[:0]
+
+		return err
+	}
+
+	for {
+		e, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, errSkipEntry) {
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+
+		if e.CreateTime.IsZero() {
+			e.CreateTime = time.Now()
+		}
+		if e.UpdateTime.IsZero() {
+			e.UpdateTime = e.CreateTime
+		}
+
+		fp := entryFingerprint(e)
+		if seen[fp] {
+			discardAttachments(e)
+			result.Duplicates++
+			continue
+		}
+		seen[fp] = true
+
+		batch = append(batch, e)
+		result.Imported++
+		if opts.Preview != nil {
+			opts.Preview(e)
+		}
+
+		if batchSize > 0 && len(batch) >= batchSize {
+			if err = flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if err = flush(); err != nil {
+		return result, err
+	}
+
+	if !opts.DryRun && result.Imported > 0 && j.git != nil {
+		_, err = j.reconcileGit()
+	}
+
+	return result, err
+}
+
+// insertEntry stores e under its own ID if that ID is free, or under a new one otherwise.
+func (j *Journal) insertEntry(b *bolt.Bucket, e Entry) error {
+	if e.ID <= 0 || b.Get(itob(e.ID)) != nil {
+		id, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		e.ID = int(id)
+	} else if uint64(e.ID) > b.Sequence() {
+		if err := b.SetSequence(uint64(e.ID)); err != nil {
+			return err
+		}
+	}
+
+	return j.putEntry(b, e)
+}
+
+// entryFingerprint identifies an entry by its creation time, content and title for duplicate detection.
+func entryFingerprint(e Entry) string {
+	h := sha256.New()
+	h.Write([]byte(e.CreateTime.UTC().Format(time.RFC3339Nano)))
+	h.Write([]byte{0})
+	h.Write([]byte(e.Content))
+	h.Write([]byte{0})
+	h.Write([]byte(e.Title))
+	return hex.EncodeToString(h.Sum(nil))
+}
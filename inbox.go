@@ -0,0 +1,193 @@
+package jrnl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// inboxQuarantine is the folder, inside the inbox, files that can't be ingested are moved to
+// when InboxConfig doesn't name one. It is hidden so the inbox's own scan passes over it.
+const inboxQuarantine = ".quarantine"
+
+// InboxConfig configures the inbox folder jrnl watches for entries written by other tools.
+type InboxConfig struct {
+	Dir        string `json:"dir"`
+	Quarantine string `json:"quarantine,omitempty"`
+}
+
+// InboxFile is the outcome of ingesting one file from the inbox.
+type InboxFile struct {
+	Path string
+	// Entry is the entry the file became. It is set for duplicates too.
+	Entry     Entry
+	Duplicate bool
+	// Err is why the file couldn't be ingested, in which case it was moved to QuarantinedTo.
+	Err           error
+	QuarantinedTo string
+}
+
+// IngestInbox adds every .md and .txt file in the inbox folder to the journal as a new entry
+// and then shreds the file. The entry is read like a note in ImportMarkdownDir, so front
+// matter can set its date, title and tags, and images next to it in the inbox are attached
+// and shredded along with it. A file that is already in the journal is shredded without
+// adding it again. Files that can't be ingested are moved to the quarantine folder along
+// with a .error file saying why.
+func (j *Journal) IngestInbox(cfg InboxConfig) ([]InboxFile, error) {
+	return j.ingestInbox(cfg, func(string, os.FileInfo) bool { return true })
+}
+
+// WatchInbox polls the inbox folder every interval until ctx is done, ingesting the files
+// dropped into it as IngestInbox does and calling report with each one. A file is only
+// ingested once it is unchanged between two polls, so files that are still being written,
+// by a sync app for instance, are left until they are complete.
+func (j *Journal) WatchInbox(ctx context.Context, cfg InboxConfig, interval time.Duration, report func(f InboxFile)) error {
+	type snapshot struct {
+		size    int64
+		modTime time.Time
+	}
+	seen := make(map[string]snapshot)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		current := make(map[string]snapshot)
+		files, err := j.ingestInbox(cfg, func(path string, info os.FileInfo) bool {
+			s := snapshot{size: info.Size(), modTime: info.ModTime()}
+			current[path] = s
+			return seen[path] == s
+		})
+		if err != nil {
+			return err
+		}
+		seen = current
+		for _, f := range files {
+			report(f)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ingestInbox ingests the notes in the inbox that ready says can be.
+func (j *Journal) ingestInbox(cfg InboxConfig, ready func(path string, info os.FileInfo) bool) ([]InboxFile, error) {
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	quarantine := cfg.Quarantine
+	if quarantine == "" {
+		quarantine = filepath.Join(dir, inboxQuarantine)
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []InboxFile
+	for _, d := range dirEntries {
+		ext := strings.ToLower(filepath.Ext(d.Name()))
+		if strings.HasPrefix(d.Name(), ".") || !d.Type().IsRegular() || (ext != ".md" && ext != ".txt") {
+			continue
+		}
+		path := filepath.Join(dir, d.Name())
+		info, serr := d.Info()
+		if serr != nil || !ready(path, info) {
+			continue
+		}
+
+		f, ierr := j.ingestInboxFile(dir, path)
+		if ierr != nil {
+			f.Err = ierr
+			if f.QuarantinedTo, err = quarantineFile(quarantine, path, ierr); err != nil {
+				return files, fmt.Errorf("quarantining %s: %w", path, err)
+			}
+		}
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+// ingestInboxFile adds the note at path to the journal and shreds it and its images. It
+// returns an error if the note couldn't be added, a failure to shred it once it has been is
+// only reported in the InboxFile.
+func (j *Journal) ingestInboxFile(dir, path string) (InboxFile, error) {
+	f := InboxFile{Path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return f, errors.New("file is empty")
+	}
+	if !utf8.Valid(data) {
+		return f, errors.New("file is not UTF-8 text")
+	}
+
+	// without a list of files, only images linked by a path inside the inbox are attached, so
+	// shredding them can't reach outside it.
+	n, err := readNote(dir, path, nil)
+	if err != nil {
+		return f, err
+	}
+	images := make([]string, 0, len(n.images))
+	for _, img := range n.images {
+		images = append(images, img)
+	}
+
+	done := false
+	res, err := j.importEntries(func() (Entry, error) {
+		if done {
+			return Entry{}, io.EOF
+		}
+		done = true
+		return n.entry, nil
+	}, func(id string) ([]byte, error) {
+		return os.ReadFile(n.images[id])
+	}, nil, ImportOptions{}, 0)
+	if err != nil {
+		return f, err
+	}
+	f.Entry = n.entry
+	f.Duplicate = res.Duplicates > 0
+
+	for _, p := range append([]string{path}, images...) {
+		if err = ShredFile(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			f.Err = fmt.Errorf("entry saved but shredding %s failed: %w", filepath.Base(p), err)
+		}
+	}
+
+	return f, nil
+}
+
+// quarantineFile moves the file at path into dir, next to a .error file holding reason,
+// and returns its new path. A file already quarantined under the same name is kept.
+func quarantineFile(dir, path string, reason error) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	dst := filepath.Join(dir, filepath.Base(path))
+	if _, err := os.Stat(dst); err == nil {
+		ext := filepath.Ext(dst)
+		dst = strings.TrimSuffix(dst, ext) + "-" + time.Now().Format("20060102T150405.000000000") + ext
+	}
+	if err := os.Rename(path, dst); err != nil {
+		return "", err
+	}
+
+	return dst, os.WriteFile(dst+".error", []byte(reason.Error()+"\n"), 0600)
+}
@@ -0,0 +1,164 @@
+package jrnl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_IngestInbox(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"thought.txt":    "picked up from the phone",
+		"meeting.md":     "---\ndate: 2023-01-05 10:00\ntags: [work]\n---\nNotes ![board](board.png) ![pic](assets/pic.png)\n",
+		"board.png":      "png bytes",
+		"assets/pic.png": "pic bytes",
+		"empty.md":       "  \n",
+		"binary.txt":     "\xff\xfe\x00garbage",
+		"photo.jpg":      "not a note",
+		".partial.md":    "hidden while syncing",
+		"sub/nested.md":  "only the top level is watched",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	j := mustNewTestJournal(t)
+
+	files, err := j.IngestInbox(InboxConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := make(map[string]InboxFile)
+	for _, f := range files {
+		results[filepath.Base(f.Path)] = f
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 files ingested or quarantined, got %+v", files)
+	}
+
+	for _, name := range []string{"thought.txt", "meeting.md"} {
+		if f := results[name]; f.Err != nil || f.Duplicate {
+			t.Errorf("%s: %+v", name, f)
+		}
+	}
+	for _, name := range []string{"empty.md", "binary.txt"} {
+		f := results[name]
+		if f.Err == nil || f.QuarantinedTo != filepath.Join(dir, ".quarantine", name) {
+			t.Errorf("expected %s to be quarantined, got %+v", name, f)
+			continue
+		}
+		reason, rerr := os.ReadFile(f.QuarantinedTo + ".error")
+		if rerr != nil || strings.TrimSpace(string(reason)) != f.Err.Error() {
+			t.Errorf("expected the reason next to %s, got %q, %v", name, reason, rerr)
+		}
+	}
+
+	var left []string
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && filepath.Dir(path) != filepath.Join(dir, ".quarantine") {
+			rel, _ := filepath.Rel(dir, path)
+			left = append(left, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if diff := cmp.Diff(left, []string{".partial.md", "photo.jpg", "sub/nested.md"}); diff != "" {
+		t.Errorf("files left in the inbox (-got, +want):\n%s", diff)
+	}
+
+	entries, err := j.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	meeting, thought := entries[0], entries[1]
+	if meeting.Content == "picked up from the phone" {
+		meeting, thought = thought, meeting
+	}
+	if meeting.CreateTime.Format("2006-01-02 15:04") != "2023-01-05 10:00" || !cmp.Equal(meeting.Tags, []string{"work"}) {
+		t.Errorf("expected the front matter to be read, got %+v", meeting)
+	}
+	if len(meeting.Attachments) != 2 {
+		t.Fatalf("expected both images to be attached, got %+v", meeting)
+	}
+	if data, _ := j.ReadAttachment(meeting.Attachments[0].ID); string(data) != "png bytes" {
+		t.Errorf("attachment = %q", data)
+	}
+	if !strings.HasPrefix(meeting.Content, "Notes ![board](attachment:") {
+		t.Errorf("expected the image link to point at the attachment, got %q", meeting.Content)
+	}
+
+	t.Run("duplicates are removed without adding them again", func(t *testing.T) {
+		path := filepath.Join(dir, "again.txt")
+		if err = os.WriteFile(path, []byte(thought.Content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		ct := thought.CreateTime
+		if err = os.Chtimes(path, ct, ct); err != nil {
+			t.Fatal(err)
+		}
+
+		files, err = j.IngestInbox(InboxConfig{Dir: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || !files[0].Duplicate {
+			t.Errorf("expected a duplicate, got %+v", files)
+		}
+		if _, serr := os.Stat(path); !os.IsNotExist(serr) {
+			t.Errorf("expected the duplicate to be removed")
+		}
+		if all, _ := j.ListEntries(); len(all) != 2 {
+			t.Errorf("expected 2 entries, got %d", len(all))
+		}
+	})
+}
+
+func TestJournal_WatchInbox(t *testing.T) {
+	dir := t.TempDir()
+	j := mustNewTestJournal(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ingested := make(chan InboxFile, 1)
+	stopped := make(chan error)
+	go func() {
+		stopped <- j.WatchInbox(ctx, InboxConfig{Dir: dir}, 10*time.Millisecond, func(f InboxFile) {
+			ingested <- f
+		})
+	}()
+
+	if err := os.WriteFile(filepath.Join(dir, "note.md"), []byte("dropped in"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case f := <-ingested:
+		if f.Err != nil || f.Entry.Content != "dropped in" {
+			t.Errorf("unexpected result %+v", f)
+		}
+	case <-ctx.Done():
+		t.Fatal("note was never ingested")
+	}
+	cancel()
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "note.md")); !os.IsNotExist(err) {
+		t.Errorf("expected the note to be removed from the inbox")
+	}
+}
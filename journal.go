@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	bolt "go.etcd.io/bbolt"
@@ -14,46 +16,100 @@ import (
 )
 
 const (
-	journalBucketName  = "journal"
-	passwordBucketName = "password"
-	passwordKey        = "pw"
+	journalBucketName    = "journal"
+	passwordBucketName   = "password"
+	syncBucketName       = "sync"
+	gitBucketName        = "git"
+	attachmentBucketName = "attachments"
+	draftBucketName      = "drafts"
+	templateBucketName   = "templates"
+	schemaBucketName     = "schemas"
+	statsBucketName      = "stats"
+	dayBucketName        = "days"
+	passwordKey          = "pw"
 )
 
+// ErrEntryNotFound is returned when there is no entry with the given ID.
+var ErrEntryNotFound = errors.New("entry not found")
+
 // Entry is an individual journal entry.
 type Entry struct {
 	ID         int
 	Content    string
 	CreateTime time.Time
 	UpdateTime time.Time
+
+	// Title is kept apart from Content for entries imported from journals that have one.
+	Title   string   `json:",omitempty"`
+	Starred bool     `json:",omitempty"`
+	Tags    []string `json:",omitempty"`
+	// Mood is the writer's mood, a position on the configured mood scale counting from 1, or 0 if not recorded.
+	Mood int `json:",omitempty"`
+
+	// Schema names the schema the entry follows, Fields holds its values in canonical form.
+	Schema string            `json:",omitempty"`
+	Fields map[string]string `json:",omitempty"`
+
+	Attachments []Attachment `json:",omitempty"`
+	// Metadata holds fields of entries imported from other apps that jrnl has no use for, as JSON.
+	Metadata map[string]json.RawMessage `json:",omitempty"`
+}
+
+// Markdown returns the entry's content with its title, if it has one, as a heading.
+func (e Entry) Markdown() string {
+	if e.Title == "" {
+		return e.Content
+	}
+	return "# " + e.Title + "\n\n" + e.Content
+}
+
+// HasTag reports whether the entry is tagged with tag, ignoring case.
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
 }
 
 // Journal manages persisting journal entries.
 type Journal struct {
 	db             *bolt.DB
 	hashedPassword string
+	remote         ObjectStore
+	// syncMu guards syncStatus, which sync operations running in the background record.
+	syncMu     sync.Mutex
+	syncStatus SyncStatus
+	git        *GitRepo
+	// moodLevels is how many levels the mood scale has, see UseMoodScale.
+	moodLevels int
 }
 
+// openWait is how long NewJournal waits for another jrnl with the journal open to close it.
+const openWait = 2 * time.Second
+
 // NewJournal returns a new instance of Journal.
 func NewJournal(dbPath string) (*Journal, error) {
-	db, err := bolt.Open(dbPath, 0666, &bolt.Options{Timeout: 2 * time.Second})
-	if err != nil {
-		return nil, err
-	}
-
-	err = db.Update(func(tx *bolt.Tx) error {
-		if _, err = tx.CreateBucketIfNotExists([]byte(journalBucketName)); err != nil {
-			return err
-		}
+	return NewJournalWait(dbPath, openWait)
+}
 
-		return nil
-	})
+// NewJournalWait is NewJournal, waiting up to wait for another jrnl with the journal open to
+// close it. It returns ErrJournalInUse if it isn't closed in time.
+func NewJournalWait(dbPath string, wait time.Duration) (*Journal, error) {
+	db, err := bolt.Open(dbPath, 0666, &bolt.Options{Timeout: wait})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, ErrJournalInUse
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	err = db.Update(func(tx *bolt.Tx) error {
-		if _, err = tx.CreateBucketIfNotExists([]byte(passwordBucketName)); err != nil {
-			return err
+		for _, name := range []string{journalBucketName, passwordBucketName, syncBucketName, gitBucketName, attachmentBucketName, draftBucketName, templateBucketName, schemaBucketName, statsBucketName, dayBucketName} {
+			if _, err = tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -74,84 +130,95 @@ func (j *Journal) Close() error {
 
 // CreateEntry stores a new entry in the journal.
 func (j *Journal) CreateEntry(content string) (Entry, error) {
+	return j.CreateEntryWithFields(content, "", nil)
+}
+
+// CreateEntryWithFields stores a new entry following schema with the given field values,
+// see SetEntryFields.
+func (j *Journal) CreateEntryWithFields(content, schema string, values map[string]string) (Entry, error) {
+	return j.CreateEntryFrom(Entry{Content: content, Schema: schema, Fields: values})
+}
+
+// CreateEntryFrom stores e as a new entry, giving it the next ID. A zero CreateTime is now,
+// its fields are checked against its schema and its mood against the mood scale.
+func (j *Journal) CreateEntryFrom(e Entry) (Entry, error) {
 	now := time.Now()
-	e := Entry{
-		Content:    content,
-		CreateTime: now,
-		UpdateTime: now,
+	if e.CreateTime.IsZero() {
+		e.CreateTime = now
+	}
+	e.UpdateTime = now
+	if err := j.checkMood(e.Mood); err != nil {
+		return Entry{}, err
 	}
 
 	err := j.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(journalBucketName))
-		id, err := b.NextSequence()
-		if err != nil {
+		var err error
+		if e.Schema, e.Fields, err = j.parseFields(tx, e.Schema, e.Fields); err != nil {
 			return err
 		}
 
-		e.ID = int(id)
-
-		buf, err := json.Marshal(e)
+		b := tx.Bucket([]byte(journalBucketName))
+		id, err := b.NextSequence()
 		if err != nil {
 			return err
 		}
 
-		encrypted, err := encrypt([]byte(j.hashedPassword), buf)
-		if err != nil {
-			return err
-		}
+		e.ID = int(id)
 
-		return b.Put(itob(e.ID), encrypted)
+		return j.putEntry(b, e)
 	})
 	if err != nil {
 		return Entry{}, err
 	}
 
+	if err = j.recordGitHistory("Create", e.ID); err != nil {
+		return e, err
+	}
+
 	return e, nil
 }
 
 // EditEntry edits an existing entry
 func (j *Journal) EditEntry(id int, content string) (Entry, error) {
-	e := Entry{
-		ID:         id,
-		Content:    content,
-		UpdateTime: time.Now(),
-	}
+	var e Entry
 
 	err := j.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(journalBucketName))
 
-		data := b.Get(itob(id))
-		decrypted, err := decrypt([]byte(j.hashedPassword), data)
+		currentEntry, err := j.getEntry(b, id)
 		if err != nil {
 			return err
 		}
 
-		var currentEntry Entry
-		if err = json.Unmarshal(decrypted, &currentEntry); err != nil {
-			return err
-		}
-
-		e.CreateTime = currentEntry.CreateTime
-
-		buf, err := json.Marshal(e)
-		if err != nil {
-			return err
-		}
+		e = currentEntry
+		e.Content = content
+		e.UpdateTime = time.Now()
 
-		encrypted, err := encrypt([]byte(j.hashedPassword), buf)
-		if err != nil {
-			return err
-		}
-
-		return b.Put(itob(e.ID), encrypted)
+		return j.putEntry(b, e)
 	})
 	if err != nil {
-		return Entry{}, nil
+		return Entry{}, err
+	}
+
+	if err = j.recordGitHistory("Edit", e.ID); err != nil {
+		return e, err
 	}
 
 	return e, nil
 }
 
+// GetEntry returns the entry with the given ID.
+func (j *Journal) GetEntry(id int) (Entry, error) {
+	var e Entry
+	err := j.db.View(func(tx *bolt.Tx) error {
+		var err error
+		e, err = j.getEntry(tx.Bucket([]byte(journalBucketName)), id)
+		return err
+	})
+
+	return e, err
+}
+
 // ListEntries lists all entries in the journal.
 func (j *Journal) ListEntries() ([]Entry, error) {
 	entries := make([]Entry, 0)
@@ -160,16 +227,11 @@ func (j *Journal) ListEntries() ([]Entry, error) {
 		b := tx.Bucket([]byte(journalBucketName))
 
 		err := b.ForEach(func(k, v []byte) error {
-			decrypted, err := decrypt([]byte(j.hashedPassword), v)
+			e, err := j.decodeEntry(v)
 			if err != nil {
 				return err
 			}
 
-			var e Entry
-			if err = json.Unmarshal(decrypted, &e); err != nil {
-				return err
-			}
-
 			entries = append(entries, e)
 
 			return nil
@@ -191,12 +253,17 @@ func (j *Journal) ListEntries() ([]Entry, error) {
 	return entries, nil
 }
 
-// DeleteEntry removes an entry from the journal.
+// DeleteEntry removes an entry and its attachments from the journal.
 func (j *Journal) DeleteEntry(id int) error {
-	return j.db.Update(func(tx *bolt.Tx) error {
+	err := j.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(journalBucketName))
-		return b.Delete(itob(id))
+		return j.deleteEntry(b, id)
 	})
+	if err != nil {
+		return err
+	}
+
+	return j.recordGitHistory("Delete", id)
 }
 
 // CreatePassword stores a user's password.
@@ -251,9 +318,86 @@ func (j *Journal) IsInitialized() (bool, error) {
 	return initialized, nil
 }
 
+// forEachEntry decrypts entries one at a time in ID order and calls fn with each,
+// so the whole journal never has to be held in memory.
+func (j *Journal) forEachEntry(fn func(e Entry) error) error {
+	return j.forEachEntryTx(func(_ *bolt.Tx, e Entry) error {
+		return fn(e)
+	})
+}
+
+// forEachEntryTx is forEachEntry for callers that need to read more from the transaction.
+func (j *Journal) forEachEntryTx(fn func(tx *bolt.Tx, e Entry) error) error {
+	return j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(journalBucketName)).ForEach(func(k, v []byte) error {
+			e, err := j.decodeEntry(v)
+			if err != nil {
+				return err
+			}
+
+			return fn(tx, e)
+		})
+	})
+}
+
+// getEntry reads and decrypts the entry stored under id in the journal bucket.
+func (j *Journal) getEntry(b *bolt.Bucket, id int) (Entry, error) {
+	data := b.Get(itob(id))
+	if data == nil {
+		return Entry{}, fmt.Errorf("%w: %d", ErrEntryNotFound, id)
+	}
+
+	return j.decodeEntry(data)
+}
+
+// putEntry encrypts e and stores it under its ID in the journal bucket, keeping its stats up to date.
+func (j *Journal) putEntry(b *bolt.Bucket, e Entry) error {
+	encrypted, err := j.encodeEntry(e)
+	if err != nil {
+		return err
+	}
+	if err = b.Put(itob(e.ID), encrypted); err != nil {
+		return err
+	}
+	// the day index finds the day the entry was on in its stats, so it goes first.
+	if err = j.putEntryDay(b.Tx(), e); err != nil {
+		return err
+	}
+
+	return j.putEntryStats(b.Tx(), e)
+}
+
+func (j *Journal) encodeEntry(e Entry) ([]byte, error) {
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return encrypt([]byte(j.hashedPassword), buf)
+}
+
+func (j *Journal) decodeEntry(data []byte) (Entry, error) {
+	decrypted, err := decrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	var e Entry
+	if err = json.Unmarshal(decrypted, &e); err != nil {
+		return Entry{}, err
+	}
+
+	return e, nil
+}
+
 // itob returns an 8-byte big endian representation of v.
 func itob(v int) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b, uint64(v))
 	return b
 }
+
+// btoi is the inverse of itob.
+func btoi(b []byte) int {
+	return int(binary.BigEndian.Uint64(b))
+}
@@ -1,6 +1,7 @@
 package jrnl
 
 import (
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -68,6 +69,44 @@ func TestJournal_CreateEntry(t *testing.T) {
 	}
 }
 
+func TestJournal_CreateEntryFrom(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	got, err := j.CreateEntryFrom(Entry{Content: "written up late", CreateTime: yesterday, Mood: 3, Tags: []string{"late"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Entry{ID: 1, Content: "written up late", CreateTime: yesterday, UpdateTime: time.Now(), Mood: 3, Tags: []string{"late"}}
+	if diff := cmp.Diff(got, want, cmpopts.EquateApproxTime(5*time.Second)); diff != "" {
+		t.Errorf("CreateEntryFrom() (-got, +want):\n%s", diff)
+	}
+	if stored, _ := j.GetEntry(got.ID); !stored.CreateTime.Equal(yesterday) {
+		t.Errorf("CreateEntryFrom() stored a create time of %v, want %v", stored.CreateTime, yesterday)
+	}
+
+	if _, err = j.CreateEntryFrom(Entry{Content: "x", Mood: ScaleMax + 1}); err == nil {
+		t.Errorf("expected a mood beyond the scale to be refused")
+	}
+}
+
+func TestJournal_GetEntry(t *testing.T) {
+	j := mustNewTestJournal(t)
+	want := mustCreateEntry(t, j, "find me")
+
+	got, err := j.GetEntry(want.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want, cmpopts.EquateApproxTime(time.Millisecond)); diff != "" {
+		t.Errorf("GetEntry() (-got, +want):\n%s", diff)
+	}
+
+	if _, err = j.GetEntry(want.ID + 1); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("GetEntry() of a missing entry = %v, want ErrEntryNotFound", err)
+	}
+}
+
 func TestJournal_EditEntry(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -358,6 +397,19 @@ func mustCreateEntry(tb testing.TB, j *Journal, content string) Entry {
 	return e
 }
 
+// mustNewTestJournal returns an authenticated journal in a new file, closed when the test ends.
+func mustNewTestJournal(tb testing.TB) *Journal {
+	tb.Helper()
+
+	f, closeFunc := mustNewTestFile(tb)
+	tb.Cleanup(closeFunc)
+
+	j, jCloseFunc := mustNewAuthenticatedJournal(tb, f.Name())
+	tb.Cleanup(jCloseFunc)
+
+	return j
+}
+
 func mustNewAuthenticatedJournal(tb testing.TB, dbPath string) (*Journal, func()) {
 	tb.Helper()
 
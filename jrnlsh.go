@@ -0,0 +1,193 @@
+package jrnl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// jrnlHeader matches the line starting an entry in a jrnl.sh text journal, e.g. "[2023-01-05 09:30] Title".
+	jrnlHeader = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2} \d{1,2}:\d{2}(?::\d{2})?(?: ?[AaPp][Mm])?)\] ?(.*)$`)
+
+	// jrnlTag matches an @tag, jrnl.sh only recognises tags at the start of a word.
+	jrnlTag = regexp.MustCompile(`(?:^|\s)@([\p{L}\p{N}_][\p{L}\p{N}_-]*)`)
+
+	// jrnlTimeLayouts are the timestamp formats jrnl.sh writes with its default and common 12 hour time formats.
+	jrnlTimeLayouts = []string{
+		"2006-01-02 15:04",
+		"2006-01-02 15:04:05",
+		"2006-01-02 3:04 PM",
+		"2006-01-02 3:04:05 PM",
+		"2006-01-02 3:04PM",
+		"2006-01-02 3:04:05PM",
+	}
+)
+
+// jrnlEntry is an entry of a jrnl.sh journal, as it appears in its JSON export.
+type jrnlEntry struct {
+	Title   string   `json:"title"`
+	Body    string   `json:"body"`
+	Date    string   `json:"date"`
+	Time    string   `json:"time"`
+	Tags    []string `json:"tags"`
+	Starred bool     `json:"starred"`
+}
+
+// ImportJrnlText reads a journal in the plain text format of the Python jrnl tool (jrnl.sh),
+// where every entry starts with a "[2006-01-02 15:04] Title" line, and adds its entries to
+// the journal in a single transaction. Entries keep their timestamps, which jrnl.sh writes
+// in local time, titles, starred flags and @tags. Text before the first entry and entries
+// with unreadable timestamps are counted as skipped.
+func (j *Journal) ImportJrnlText(r io.Reader, opts ImportOptions) (ImportResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return j.importEntries(jrnlEntryReader(parseJrnlText(string(data))), nil, nil, opts, 0)
+}
+
+// ImportJrnlJSON reads a journal exported by jrnl.sh with --export json and adds its entries
+// to the journal in a single transaction, the same way ImportJrnlText does.
+func (j *Journal) ImportJrnlJSON(r io.Reader, opts ImportOptions) (ImportResult, error) {
+	var export struct {
+		Entries []jrnlEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return ImportResult{}, fmt.Errorf("not a jrnl.sh JSON export: %w", err)
+	}
+
+	return j.importEntries(jrnlEntryReader(export.Entries), nil, nil, opts, 0)
+}
+
+// jrnlEntryReader returns a function yielding each of entries in turn for importEntries.
+func jrnlEntryReader(entries []jrnlEntry) func() (Entry, error) {
+	return func() (Entry, error) {
+		if len(entries) == 0 {
+			return Entry{}, io.EOF
+		}
+		je := entries[0]
+		entries = entries[1:]
+
+		return je.toEntry()
+	}
+}
+
+func (je jrnlEntry) toEntry() (Entry, error) {
+	created, err := parseJrnlTime(strings.TrimSpace(je.Date + " " + je.Time))
+	if err != nil {
+		return Entry{}, err
+	}
+
+	e := Entry{
+		Content:    je.Body,
+		CreateTime: created,
+		Title:      je.Title,
+		Starred:    je.Starred,
+	}
+	for _, t := range je.Tags {
+		e = withTag(e, strings.TrimLeft(t, "@#"))
+	}
+
+	return e, nil
+}
+
+// parseJrnlText splits a jrnl.sh text journal into its entries. Text before the first
+// entry is returned as an entry without a timestamp so it is counted as skipped.
+func parseJrnlText(text string) []jrnlEntry {
+	var (
+		entries  []jrnlEntry
+		preamble bool
+		lines    []string
+	)
+	flush := func() {
+		if len(entries) > 0 {
+			entries[len(entries)-1].fill(lines)
+		}
+		lines = lines[:0]
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		m := jrnlHeader.FindStringSubmatch(line)
+		if m == nil {
+			if len(entries) == 0 && strings.TrimSpace(line) != "" {
+				preamble = true
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		flush()
+		entries = append(entries, jrnlEntry{Date: m[1]})
+		lines = append(lines, m[2])
+	}
+	flush()
+
+	if preamble {
+		entries = append([]jrnlEntry{{}}, entries...)
+	}
+
+	return entries
+}
+
+// fill sets the entry's title, body, starred flag and tags from its text, where lines[0]
+// is the rest of the header line. Like jrnl.sh, the title is the header's first sentence.
+func (je *jrnlEntry) fill(lines []string) {
+	first := strings.TrimSpace(lines[0])
+	if strings.HasSuffix(first, "*") || strings.HasPrefix(first, "*") {
+		je.Starred = true
+		first = strings.TrimSpace(strings.Trim(first, "*"))
+	}
+
+	je.Title, first = splitJrnlTitle(first)
+
+	body := strings.Join(append([]string{first}, lines[1:]...), "\n")
+	je.Body = strings.Trim(body, " \n")
+
+	for _, m := range jrnlTag.FindAllStringSubmatch(je.Title+"\n"+je.Body, -1) {
+		je.Tags = append(je.Tags, m[1])
+	}
+}
+
+// splitJrnlTitle splits the first sentence off a line, at the first run of sentence ending
+// punctuation followed by a space.
+func splitJrnlTitle(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		if !strings.ContainsRune(".?!", rune(line[i])) {
+			continue
+		}
+		end := i
+		for end+1 < len(line) && strings.ContainsRune(".?!", rune(line[end+1])) {
+			end++
+		}
+		if end+1 < len(line) && line[end+1] == ' ' {
+			return line[:end+1], strings.TrimSpace(line[end+1:])
+		}
+		i = end
+	}
+
+	return line, ""
+}
+
+func parseJrnlTime(s string) (time.Time, error) {
+	for _, layout := range jrnlTimeLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(s), time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: bad timestamp %q", errSkipEntry, s)
+}
+
+// withTag returns e tagged with tag, unless it already is or tag is empty.
+func withTag(e Entry, tag string) Entry {
+	if tag == "" || e.HasTag(tag) {
+		return e
+	}
+	e.Tags = append(e.Tags, tag)
+	return e
+}
@@ -0,0 +1,130 @@
+package jrnl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_ImportJrnlText(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	journal := `exported from my old laptop
+
+[2023-01-05 09:30] Went hiking with @Sam. It was cold
+but the view was worth it. @outdoors
+
+[2023-01-06 21:15] Quiet day at @work! *
+
+[2023-01-07 7:05 PM] No body here
+
+[2023-02-30 10:00] Not a real day.
+`
+
+	res, err := j.ImportJrnlText(strings.NewReader(journal), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res, ImportResult{Imported: 3, Skipped: 2}); diff != "" {
+		t.Errorf("ImportJrnlText() (-got, +want):\n%s", diff)
+	}
+
+	got, err := j.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{
+			ID:         3,
+			CreateTime: time.Date(2023, time.January, 7, 19, 5, 0, 0, time.Local),
+			UpdateTime: time.Date(2023, time.January, 7, 19, 5, 0, 0, time.Local),
+			Title:      "No body here",
+		},
+		{
+			ID:         2,
+			CreateTime: time.Date(2023, time.January, 6, 21, 15, 0, 0, time.Local),
+			UpdateTime: time.Date(2023, time.January, 6, 21, 15, 0, 0, time.Local),
+			Title:      "Quiet day at @work!",
+			Starred:    true,
+			Tags:       []string{"work"},
+		},
+		{
+			ID:         1,
+			Content:    "It was cold\nbut the view was worth it. @outdoors",
+			CreateTime: time.Date(2023, time.January, 5, 9, 30, 0, 0, time.Local),
+			UpdateTime: time.Date(2023, time.January, 5, 9, 30, 0, 0, time.Local),
+			Title:      "Went hiking with @Sam.",
+			Tags:       []string{"Sam", "outdoors"},
+		},
+	}
+	if diff := cmp.Diff(got, want, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+		t.Errorf("ListEntries() (-got, +want):\n%s", diff)
+	}
+
+	res, err = j.ImportJrnlText(strings.NewReader(journal), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res, ImportResult{Duplicates: 3, Skipped: 2}); diff != "" {
+		t.Errorf("re-import (-got, +want):\n%s", diff)
+	}
+}
+
+func TestJournal_ImportJrnlJSON(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	export := `{
+  "tags": {"@work": 1},
+  "entries": [
+    {"title": "Standup ran long.", "body": "Again.", "date": "2023-03-01", "time": "09:00", "tags": ["@work"], "starred": true},
+    {"title": "Lazy sunday", "body": "", "date": "2023-03-05", "time": "14:20", "tags": [], "starred": false},
+    {"title": "Broken", "body": "", "date": "yesterday", "time": "", "tags": [], "starred": false}
+  ]
+}`
+
+	res, err := j.ImportJrnlJSON(strings.NewReader(export), ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res, ImportResult{Imported: 2, Skipped: 1}); diff != "" {
+		t.Errorf("ImportJrnlJSON() dry run (-got, +want):\n%s", diff)
+	}
+	if got, _ := j.ListEntries(); len(got) != 0 {
+		t.Fatalf("dry run wrote %d entries", len(got))
+	}
+
+	if _, err = j.ImportJrnlJSON(strings.NewReader(export), ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := j.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{
+			ID:         2,
+			CreateTime: time.Date(2023, time.March, 5, 14, 20, 0, 0, time.Local),
+			UpdateTime: time.Date(2023, time.March, 5, 14, 20, 0, 0, time.Local),
+			Title:      "Lazy sunday",
+		},
+		{
+			ID:         1,
+			Content:    "Again.",
+			CreateTime: time.Date(2023, time.March, 1, 9, 0, 0, 0, time.Local),
+			UpdateTime: time.Date(2023, time.March, 1, 9, 0, 0, 0, time.Local),
+			Title:      "Standup ran long.",
+			Starred:    true,
+			Tags:       []string{"work"},
+		},
+	}
+	if diff := cmp.Diff(got, want, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+		t.Errorf("ListEntries() (-got, +want):\n%s", diff)
+	}
+
+	if _, err = j.ImportJrnlJSON(strings.NewReader(`[1, 2]`), ImportOptions{}); err == nil {
+		t.Errorf("expected an error importing something that isn't a jrnl.sh export")
+	}
+}
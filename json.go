@@ -0,0 +1,343 @@
+package jrnl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// JSONSchema and JSONSchemaVersion identify the JSON export format.
+//
+// A JSON export is either a single document:
+//
+//	{
+//	  "schema": "jrnl",
+//	  "version": 1,
+//	  "exportedAt": "2023-01-05T09:30:00Z",
+//	  "entries": [
+//	    {"id": 1, "content": "...", "createTime": "...", "updateTime": "..."}
+//	  ]
+//	}
+//
+// or newline delimited JSON (NDJSON), where the first line is the header object
+// without "entries" and every following line is one entry object. In a document the
+// header fields come before "entries" so the entries can be streamed.
+//
+// Entry fields:
+//
+//	id          integer  the entry's ID in the journal it was exported from
+//	content     string   the entry's markdown content
+//	createTime  string   RFC 3339 timestamp with nanoseconds and time zone offset
+//	updateTime  string   RFC 3339 timestamp with nanoseconds and time zone offset
+//	title       string   optional, the entry's title when it's kept apart from its content
+//	starred     boolean  optional, whether the entry is starred
+//	tags        array    optional, the entry's tags as strings without a leading @
+//	mood        integer  optional, the writer's mood from 1, the worst on their mood scale
+//	fieldSchema string   optional, the name of the schema the entry's fields follow
+//	fields      object   optional, the values of the entry's fields as strings, see Field
+//	attachments array    optional, files attached to the entry, see below
+//	metadata    object   optional, fields of entries imported from other apps, kept as they were
+//
+// Attachment fields:
+//
+//	id          string   the attachment's ID, the entry's content links to it as attachment:<id>
+//	name        string   the attachment's file name
+//	mediaType   string   the attachment's media type, e.g. image/jpeg
+//	data        string   the attachment's contents, base64 encoded
+//
+// Fields may be added within a version; readers must ignore fields they don't know.
+// Changes that remove or reinterpret a field bump the version.
+const (
+	JSONSchema        = "jrnl"
+	JSONSchemaVersion = 1
+)
+
+// JSONHeader identifies a JSON export.
+type JSONHeader struct {
+	Schema     string    `json:"schema"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// JSONEntry is an entry as it appears in a JSON export.
+type JSONEntry struct {
+	ID         int       `json:"id"`
+	Content    string    `json:"content"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+	Title      string    `json:"title,omitempty"`
+	Starred    bool      `json:"starred,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Mood       int       `json:"mood,omitempty"`
+
+	FieldSchema string            `json:"fieldSchema,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+
+	Attachments []JSONAttachment           `json:"attachments,omitempty"`
+	Metadata    map[string]json.RawMessage `json:"metadata,omitempty"`
+}
+
+// JSONAttachment is an attachment as it appears in a JSON export.
+type JSONAttachment struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MediaType string `json:"mediaType"`
+	Data      []byte `json:"data"`
+}
+
+// JSONExportOptions configures ExportJSON.
+type JSONExportOptions struct {
+	// NDJSON writes a header line followed by one entry per line instead of a single document.
+	NDJSON bool
+}
+
+// ExportJSON streams every entry to w in the versioned JSON format described by JSONSchema.
+// Entries are decrypted and written one at a time so huge journals don't have to fit in memory.
+func (j *Journal) ExportJSON(w io.Writer, opts JSONExportOptions) error {
+	bw := bufio.NewWriter(w)
+
+	header, err := json.Marshal(JSONHeader{
+		Schema:     JSONSchema,
+		Version:    JSONSchemaVersion,
+		ExportedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.NDJSON {
+		header = append(header, '\n')
+	} else {
+		// splice the entries array into the header object.
+		header = append(header[:len(header)-1], `,"entries":[`...)
+	}
+	if _, err = bw.Write(header); err != nil {
+		return err
+	}
+
+	first := true
+	err = j.forEachEntryTx(func(tx *bolt.Tx, e Entry) error {
+		je := toJSONEntry(e)
+		attachments := je.Attachments[:0]
+		for _, a := range je.Attachments {
+			data, err := j.readAttachment(tx, a.ID)
+			if errors.Is(err, ErrAttachmentNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			a.Data = data
+			attachments = append(attachments, a)
+		}
+		je.Attachments = attachments
+
+		buf, err := json.Marshal(je)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case opts.NDJSON:
+			buf = append(buf, '\n')
+		case first:
+			buf = append([]byte("\n"), buf...)
+		default:
+			buf = append([]byte(",\n"), buf...)
+		}
+		first = false
+
+		_, err = bw.Write(buf)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if !opts.NDJSON {
+		if _, err = bw.WriteString("\n]}\n"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ImportJSON reads a JSON export, either a single document or NDJSON, and adds its entries
+// to the journal. Entries are streamed so huge exports don't have to fit in memory.
+func (j *Journal) ImportJSON(r io.Reader, opts ImportOptions) (ImportResult, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	next, err := jsonEntryReader(dec)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	// attachment data is held until the batch its entry is in gets written, or until the
+	// entry is discarded as a duplicate or by a dry run.
+	files := make(map[string][]byte)
+	withFiles := func() (Entry, error) {
+		e, attachments, err := next()
+		if err != nil {
+			return e, err
+		}
+		for _, a := range attachments {
+			files[a.ID] = a.Data
+		}
+		return e, nil
+	}
+	readAttachment := func(id string) ([]byte, error) {
+		data, ok := files[id]
+		if !ok {
+			return nil, fmt.Errorf("attachment %s has no data", id)
+		}
+		delete(files, id)
+		return data, nil
+	}
+	discard := func(id string) {
+		delete(files, id)
+	}
+
+	return j.importEntries(withFiles, readAttachment, discard, opts, importBatchSize)
+}
+
+// jsonEntryReader reads the header object from dec and returns a function yielding each
+// entry and its attachments in turn, whether they are inside the header's "entries" array
+// or follow it as NDJSON.
+func jsonEntryReader(dec *json.Decoder) (func() (Entry, []JSONAttachment, error), error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var header JSONHeader
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok {
+		case "schema":
+			err = dec.Decode(&header.Schema)
+		case "version":
+			err = dec.Decode(&header.Version)
+		case "exportedAt":
+			err = dec.Decode(&header.ExportedAt)
+		case "entries":
+			if err = checkJSONHeader(header); err != nil {
+				return nil, err
+			}
+			if err = expectDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			return documentEntryReader(dec), nil
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	if err := checkJSONHeader(header); err != nil {
+		return nil, err
+	}
+
+	return func() (Entry, []JSONAttachment, error) {
+		var e JSONEntry
+		if err := dec.Decode(&e); err != nil {
+			return Entry{}, nil, err
+		}
+		return e.toEntry(), e.Attachments, nil
+	}, nil
+}
+
+func documentEntryReader(dec *json.Decoder) func() (Entry, []JSONAttachment, error) {
+	done := false
+	return func() (Entry, []JSONAttachment, error) {
+		if done || !dec.More() {
+			done = true
+			return Entry{}, nil, io.EOF
+		}
+
+		var e JSONEntry
+		if err := dec.Decode(&e); err != nil {
+			return Entry{}, nil, err
+		}
+		return e.toEntry(), e.Attachments, nil
+	}
+}
+
+func checkJSONHeader(h JSONHeader) error {
+	if h.Schema != JSONSchema {
+		return fmt.Errorf("not a jrnl JSON export: schema %q", h.Schema)
+	}
+	if h.Version < 1 || h.Version > JSONSchemaVersion {
+		return fmt.Errorf("unsupported jrnl JSON export version %d, this build supports up to %d", h.Version, JSONSchemaVersion)
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("unexpected end of JSON input, wanted %q", want)
+	}
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("invalid JSON export: wanted %q, got %v", want, tok)
+	}
+	return nil
+}
+
+func toJSONEntry(e Entry) JSONEntry {
+	je := JSONEntry{
+		ID:         e.ID,
+		Content:    e.Content,
+		CreateTime: e.CreateTime,
+		UpdateTime: e.UpdateTime,
+		Title:      e.Title,
+		Starred:    e.Starred,
+		Tags:       e.Tags,
+		Mood:       e.Mood,
+		Metadata:   e.Metadata,
+
+		FieldSchema: e.Schema,
+		Fields:      e.Fields,
+	}
+	for _, a := range e.Attachments {
+		je.Attachments = append(je.Attachments, JSONAttachment{ID: a.ID, Name: a.Name, MediaType: a.MediaType})
+	}
+	return je
+}
+
+func (e JSONEntry) toEntry() Entry {
+	entry := Entry{
+		ID:         e.ID,
+		Content:    e.Content,
+		CreateTime: e.CreateTime,
+		UpdateTime: e.UpdateTime,
+		Title:      e.Title,
+		Starred:    e.Starred,
+		Tags:       e.Tags,
+		Mood:       e.Mood,
+		Schema:     e.FieldSchema,
+		Fields:     e.Fields,
+		Metadata:   e.Metadata,
+	}
+	for _, a := range e.Attachments {
+		entry.Attachments = append(entry.Attachments, Attachment{ID: a.ID, Name: a.Name, MediaType: a.MediaType})
+	}
+	return entry
+}
@@ -0,0 +1,163 @@
+package jrnl
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_ExportJSON(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts JSONExportOptions
+	}{
+		{name: "document"},
+		{name: "ndjson", opts: JSONExportOptions{NDJSON: true}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			src := mustNewTestJournal(t)
+
+			day := time.Date(2023, time.January, 5, 9, 30, 0, 123456789, time.FixedZone("EST", -5*60*60))
+			mustPutEntry(t, src, Entry{ID: 2, Content: "first", CreateTime: day, UpdateTime: day.Add(time.Hour)})
+			mustPutEntry(t, src, Entry{ID: 7, Content: "second\nwith \"quotes\"", CreateTime: day.AddDate(0, 0, 1), UpdateTime: day.AddDate(0, 0, 1), Title: "Second", Starred: true, Tags: []string{"work"}})
+
+			want, err := src.ListEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err = src.ExportJSON(&buf, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if tt.opts.NDJSON && strings.Count(buf.String(), "\n") != 3 {
+				t.Errorf("ExportJSON() = %q, want a header line and one line per entry", buf.String())
+			}
+
+			dst := mustNewTestJournal(t)
+			res, err := dst.ImportJSON(bytes.NewReader(buf.Bytes()), ImportOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(res, ImportResult{Imported: 2}); diff != "" {
+				t.Errorf("ImportJSON() (-got, +want):\n%s", diff)
+			}
+
+			got, err := dst.ListEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, want, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+				t.Errorf("round trip (-got, +want):\n%s", diff)
+			}
+
+			res, err = dst.ImportJSON(bytes.NewReader(buf.Bytes()), ImportOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(res, ImportResult{Duplicates: 2}); diff != "" {
+				t.Errorf("ImportJSON() again (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJournal_ImportJSON(t *testing.T) {
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		j := mustNewTestJournal(t)
+
+		doc := `{"schema":"jrnl","version":1,"entries":[{"id":1,"content":"hi","createTime":"2023-01-05T09:30:00Z","updateTime":"2023-01-05T09:30:00Z"}]}`
+		res, err := j.ImportJSON(strings.NewReader(doc), ImportOptions{DryRun: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Imported != 1 {
+			t.Errorf("ImportJSON() imported = %d, want 1", res.Imported)
+		}
+
+		entries, err := j.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("dry run wrote %d entries", len(entries))
+		}
+	})
+
+	t.Run("ids already in use are reassigned", func(t *testing.T) {
+		j := mustNewTestJournal(t)
+		mustCreateEntry(t, j, "already here")
+
+		doc := `{"schema":"jrnl","version":1,"exportedAt":"2023-01-05T09:30:00Z","unknown":[1,2],"entries":[{"id":1,"content":"imported","createTime":"2023-01-05T09:30:00Z","updateTime":"2023-01-05T09:30:00Z","mood":3}]}`
+		if _, err := j.ImportJSON(strings.NewReader(doc), ImportOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		entries, err := j.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 || entries[0].ID != 2 || entries[0].Content != "imported" {
+			t.Errorf("ListEntries() = %+v, want the import stored as entry 2", entries)
+		}
+	})
+
+	t.Run("attachments of entries that aren't written are discarded", func(t *testing.T) {
+		j := mustNewTestJournal(t)
+
+		day := time.Date(2023, time.January, 5, 9, 30, 0, 0, time.UTC)
+		entries := []Entry{
+			{Content: "photo", CreateTime: day, Attachments: []Attachment{{ID: "a"}}},
+			{Content: "photo", CreateTime: day, Attachments: []Attachment{{ID: "b"}}},
+		}
+		for _, dryRun := range []bool{true, false} {
+			var read, discarded []string
+			i := 0
+			_, err := j.importEntries(func() (Entry, error) {
+				if i == len(entries) {
+					return Entry{}, io.EOF
+				}
+				i++
+				return entries[i-1], nil
+			}, func(id string) ([]byte, error) {
+				read = append(read, id)
+				return []byte("data"), nil
+			}, func(id string) {
+				discarded = append(discarded, id)
+			}, ImportOptions{DryRun: dryRun}, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantDiscarded := []string{"b"}
+			if dryRun {
+				wantDiscarded = []string{"b", "a"}
+			}
+			if diff := cmp.Diff(discarded, wantDiscarded); diff != "" {
+				t.Errorf("dry run %t discarded (-got, +want):\n%s", dryRun, diff)
+			}
+			if len(read)+len(discarded) != len(entries) {
+				t.Errorf("dry run %t read %q and discarded %q, want each attachment once", dryRun, read, discarded)
+			}
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, doc := range []string{
+			``,
+			`[]`,
+			`{"schema":"other","version":1,"entries":[]}`,
+			`{"schema":"jrnl","version":99,"entries":[]}`,
+			`{"schema":"jrnl","version":1,"entries":[{"id":"one"}]}`,
+		} {
+			j := mustNewTestJournal(t)
+			if _, err := j.ImportJSON(strings.NewReader(doc), ImportOptions{}); err == nil {
+				t.Errorf("ImportJSON(%q) expected an error", doc)
+			}
+		}
+	})
+}
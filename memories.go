@@ -0,0 +1,215 @@
+package jrnl
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// OnThisDay returns the entries written on the month and day of now in earlier years, newest
+// first. On the 28th of February of a year that isn't a leap year it includes the entries of
+// the 29th. Days are calendar days in the location of now. The entries are found with the
+// day index, so only those written within a day of the date are looked at, and only the
+// entries returned are decrypted.
+func (j *Journal) OnThisDay(now time.Time) ([]Entry, error) {
+	if err := j.ensureDayIndex(); err != nil {
+		return nil, err
+	}
+
+	loc := now.Location()
+	leapDay := now.Month() == time.February && now.Day() == 28 && startOfDay(now).AddDate(0, 0, 1).Month() == time.March
+
+	// the index is by day in UTC, which can be a day either side of the day in loc. 2000 is
+	// a leap year, so the 29th of February is among the days when it's wanted.
+	last := 1
+	if leapDay {
+		last = 2
+	}
+	var ids []int
+	err := j.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket([]byte(dayBucketName)).Cursor()
+		for d := -1; d <= last; d++ {
+			day := time.Date(2000, now.Month(), now.Day()+d, 0, 0, 0, 0, time.UTC)
+			prefix := j.dayKey(day.Month(), day.Day())
+			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+				created, err := j.decodeEntryDay(v)
+				if err != nil {
+					return err
+				}
+				t := created.In(loc)
+				if t.Year() >= now.Year() || t.Month() != now.Month() {
+					continue
+				}
+				if t.Day() == now.Day() || (leapDay && t.Day() == 29) {
+					ids = append(ids, btoi(k[len(prefix):]))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := j.getEntries(ids)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreateTime.After(entries[j].CreateTime)
+	})
+
+	return entries, nil
+}
+
+// RandomEntry returns an entry picked at random from those written before the day of now.
+// It returns ErrEntryNotFound if there aren't any.
+func (j *Journal) RandomEntry(now time.Time) (Entry, error) {
+	today := startOfDay(now)
+
+	var ids []int
+	err := j.forEachEntryStats(func(id int, es entryStats) error {
+		if es.CreateTime.Before(today) {
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+	if len(ids) == 0 {
+		return Entry{}, fmt.Errorf("%w: nothing written before today", ErrEntryNotFound)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(ids))))
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return j.GetEntry(ids[n.Int64()])
+}
+
+// getEntries returns the entries with the given IDs.
+func (j *Journal) getEntries(ids []int) ([]Entry, error) {
+	entries := make([]Entry, 0, len(ids))
+	err := j.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(journalBucketName))
+		for _, id := range ids {
+			e, err := j.getEntry(b, id)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, e)
+		}
+		return nil
+	})
+
+	return entries, err
+}
+
+// The day index lists entries by the month and day they were created on, in UTC, so that
+// OnThisDay doesn't have to look at every entry. Its keys are a keyed hash of the month and
+// day followed by the entry's ID, and its values the entry's encrypted creation time, so it
+// doesn't give away when entries were written.
+
+// dayKey returns the start of the day index keys of the entries created on month and day.
+func (j *Journal) dayKey(month time.Month, day int) []byte {
+	mac := hmac.New(sha256.New, []byte(j.hashedPassword))
+	fmt.Fprintf(mac, "day index %02d-%02d", int(month), day)
+	return mac.Sum(nil)[:8]
+}
+
+// dayIndexKey returns the day index key of entry id created at created.
+func (j *Journal) dayIndexKey(created time.Time, id int) []byte {
+	created = created.UTC()
+	return append(j.dayKey(created.Month(), created.Day()), itob(id)...)
+}
+
+// putEntryDay indexes e under the day it was created on, removing it from the day it was
+// on before if that has changed. The entry's stats must not have been updated yet.
+func (j *Journal) putEntryDay(tx *bolt.Tx, e Entry) error {
+	days := tx.Bucket([]byte(dayBucketName))
+	if v := tx.Bucket([]byte(statsBucketName)).Get(itob(e.ID)); v != nil {
+		old, err := j.decodeEntryStats(v)
+		if err != nil {
+			return err
+		}
+		if err = days.Delete(j.dayIndexKey(old.CreateTime, e.ID)); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(e.CreateTime)
+	if err != nil {
+		return err
+	}
+	encrypted, err := encrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return err
+	}
+
+	return days.Put(j.dayIndexKey(e.CreateTime, e.ID), encrypted)
+}
+
+func (j *Journal) decodeEntryDay(data []byte) (time.Time, error) {
+	decrypted, err := decrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var created time.Time
+	err = json.Unmarshal(decrypted, &created)
+	return created, err
+}
+
+// ensureDayIndex rebuilds the day index from the entry stats if it doesn't hold every entry,
+// as in a journal from before it was kept.
+func (j *Journal) ensureDayIndex() error {
+	if err := j.ensureStatsIndex(); err != nil {
+		return err
+	}
+
+	var stale bool
+	err := j.db.View(func(tx *bolt.Tx) error {
+		entries := tx.Bucket([]byte(journalBucketName)).Stats().KeyN
+		stale = entries != tx.Bucket([]byte(dayBucketName)).Stats().KeyN
+		return nil
+	})
+	if err != nil || !stale {
+		return err
+	}
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		if derr := tx.DeleteBucket([]byte(dayBucketName)); derr != nil {
+			return derr
+		}
+		days, cerr := tx.CreateBucket([]byte(dayBucketName))
+		if cerr != nil {
+			return cerr
+		}
+
+		return tx.Bucket([]byte(statsBucketName)).ForEach(func(k, v []byte) error {
+			es, err := j.decodeEntryStats(v)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(es.CreateTime)
+			if err != nil {
+				return err
+			}
+			encrypted, err := encrypt([]byte(j.hashedPassword), data)
+			if err != nil {
+				return err
+			}
+			return days.Put(j.dayIndexKey(es.CreateTime, btoi(k)), encrypted)
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package jrnl
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestJournal_OnThisDay(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+	mustPutEntry(t, j, Entry{ID: 1, Content: "two years ago", CreateTime: at(2021, time.March, 14)})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "a year ago", CreateTime: at(2022, time.March, 14)})
+	mustPutEntry(t, j, Entry{ID: 3, Content: "the day after", CreateTime: at(2022, time.March, 15)})
+	mustPutEntry(t, j, Entry{ID: 4, Content: "today", CreateTime: at(2023, time.March, 14)})
+	mustPutEntry(t, j, Entry{ID: 5, Content: "leap day", CreateTime: at(2020, time.February, 29)})
+
+	entries, err := j.OnThisDay(at(2023, time.March, 14))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != 2 || entries[1].ID != 1 {
+		t.Errorf("OnThisDay() = %v, want entries 2 and 1", entries)
+	}
+
+	if entries, err = j.OnThisDay(at(2023, time.February, 28)); err != nil || len(entries) != 1 || entries[0].ID != 5 {
+		t.Errorf("OnThisDay() on the 28th of February = %v, %v, want the leap day entry", entries, err)
+	}
+	if entries, err = j.OnThisDay(at(2024, time.February, 28)); err != nil || len(entries) != 0 {
+		t.Errorf("OnThisDay() on the 28th of February of a leap year = %v, %v, want none", entries, err)
+	}
+
+	// moving an entry to another day moves it in the index, deleting it takes it out.
+	mustPutEntry(t, j, Entry{ID: 3, Content: "the day after", CreateTime: at(2022, time.March, 14).Add(time.Hour)})
+	if err = j.DeleteEntry(1); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err = j.OnThisDay(at(2023, time.March, 14)); err != nil || len(entries) != 2 || entries[0].ID != 3 || entries[1].ID != 2 {
+		t.Errorf("OnThisDay() after a move and a delete = %v, %v, want entries 3 and 2", entries, err)
+	}
+
+	// a journal from before the index was kept has it built the first time it's needed.
+	err = j.db.Update(func(tx *bolt.Tx) error {
+		if derr := tx.DeleteBucket([]byte(dayBucketName)); derr != nil {
+			return derr
+		}
+		_, cerr := tx.CreateBucket([]byte(dayBucketName))
+		return cerr
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, err = j.OnThisDay(at(2023, time.March, 14)); err != nil || len(entries) != 2 {
+		t.Errorf("OnThisDay() without an index = %v, %v, want entries 3 and 2", entries, err)
+	}
+}
+
+func TestJournal_RandomEntry(t *testing.T) {
+	j := mustNewTestJournal(t)
+	now := time.Date(2023, time.March, 14, 12, 0, 0, 0, time.UTC)
+
+	mustPutEntry(t, j, Entry{ID: 1, Content: "today", CreateTime: now})
+	if _, err := j.RandomEntry(now); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("RandomEntry() with only today's entry error = %v, want ErrEntryNotFound", err)
+	}
+
+	mustPutEntry(t, j, Entry{ID: 2, Content: "yesterday", CreateTime: now.AddDate(0, 0, -1)})
+	for i := 0; i < 5; i++ {
+		e, err := j.RandomEntry(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.ID != 2 {
+			t.Errorf("RandomEntry() = entry %d, want 2", e.ID)
+		}
+	}
+}
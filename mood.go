@@ -0,0 +1,283 @@
+package jrnl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// MoodLevel describes one step of the mood scale.
+type MoodLevel struct {
+	Emoji string `json:"emoji,omitempty"`
+	Label string `json:"label,omitempty"`
+}
+
+// String is the level's emoji and label, whichever it has.
+func (l MoodLevel) String() string {
+	return strings.TrimSpace(l.Emoji + " " + l.Label)
+}
+
+// DefaultMoodScale is the mood scale used unless the config sets one.
+var DefaultMoodScale = []MoodLevel{
+	{Emoji: "😞", Label: "awful"},
+	{Emoji: "🙁", Label: "bad"},
+	{Emoji: "😐", Label: "okay"},
+	{Emoji: "🙂", Label: "good"},
+	{Emoji: "😄", Label: "great"},
+}
+
+// MoodConfig configures mood tracking.
+type MoodConfig struct {
+	// Scale lists the moods from worst to best, at most ScaleMax of them. An entry's Mood is
+	// the position of its mood in the scale, counting from 1.
+	Scale []MoodLevel `json:"scale,omitempty"`
+	// Off stops the editor asking for a mood when an entry is first saved.
+	Off bool `json:"off,omitempty"`
+}
+
+// MoodScale returns the configured scale, or DefaultMoodScale if there isn't one. c may be nil.
+func (c *MoodConfig) MoodScale() []MoodLevel {
+	if c == nil || len(c.Scale) == 0 {
+		return DefaultMoodScale
+	}
+	if len(c.Scale) > ScaleMax {
+		return c.Scale[:ScaleMax]
+	}
+	return c.Scale
+}
+
+// UseMoodScale sets the mood scale moods are checked against, DefaultMoodScale until it's
+// called. Only the number of levels matters, at most ScaleMax.
+func (j *Journal) UseMoodScale(scale []MoodLevel) {
+	j.moodLevels = len(scale)
+	if j.moodLevels > ScaleMax {
+		j.moodLevels = ScaleMax
+	}
+}
+
+// checkMood returns an error unless mood is a level of the mood scale, or 0 for none.
+func (j *Journal) checkMood(mood int) error {
+	levels := j.moodLevels
+	if levels == 0 {
+		levels = len(DefaultMoodScale)
+	}
+	if mood < 0 || mood > levels {
+		return fmt.Errorf("invalid mood %d: want 0 to %d", mood, levels)
+	}
+	return nil
+}
+
+// SetMood records the mood of entry id, 1 to the number of levels of the mood scale, or
+// clears it if mood is 0.
+func (j *Journal) SetMood(id, mood int) (Entry, error) {
+	if err := j.checkMood(mood); err != nil {
+		return Entry{}, err
+	}
+
+	var e Entry
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(journalBucketName))
+
+		var err error
+		if e, err = j.getEntry(b, id); err != nil {
+			return err
+		}
+		e.Mood = mood
+		e.UpdateTime = time.Now()
+
+		return j.putEntry(b, e)
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if err = j.recordGitHistory("Edit", e.ID); err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
+
+// MoodPoint is the mood recorded with one entry.
+type MoodPoint struct {
+	EntryID    int
+	CreateTime time.Time
+	Mood       int
+}
+
+// MonthlyMood is the average mood of the entries written in a month.
+type MonthlyMood struct {
+	// Month is midnight on the first of the month.
+	Month   time.Time
+	Average float64
+	Count   int
+}
+
+// MoodReport summarizes the moods recorded in a span of time.
+type MoodReport struct {
+	// From and To are the span the report covers, From inclusive and To exclusive. They are
+	// the days of the first and last mood if they weren't given.
+	From, To time.Time
+	// Points are the moods recorded, oldest first.
+	Points  []MoodPoint
+	Average float64
+	// ByWeekday averages the moods of entries written on each day of the week, indexed by
+	// time.Weekday. Days without any are 0.
+	ByWeekday [7]float64
+	// ByMonth averages moods by month, oldest first. Months without any are left out.
+	ByMonth []MonthlyMood
+}
+
+// MoodReport summarizes the moods of entries created at or after from and before to,
+// either of which may be zero.
+func (j *Journal) MoodReport(from, to time.Time) (MoodReport, error) {
+	r := MoodReport{From: from, To: to}
+	err := j.forEachEntryStats(func(id int, es entryStats) error {
+		if es.Mood == 0 || (!from.IsZero() && es.CreateTime.Before(from)) || (!to.IsZero() && !es.CreateTime.Before(to)) {
+			return nil
+		}
+		r.Points = append(r.Points, MoodPoint{EntryID: id, CreateTime: es.CreateTime, Mood: es.Mood})
+		return nil
+	})
+	if err != nil {
+		return MoodReport{}, err
+	}
+	if len(r.Points) == 0 {
+		return r, nil
+	}
+
+	sort.Slice(r.Points, func(i, j int) bool {
+		return r.Points[i].CreateTime.Before(r.Points[j].CreateTime)
+	})
+	if r.From.IsZero() {
+		r.From = startOfDay(r.Points[0].CreateTime)
+	}
+	if r.To.IsZero() {
+		r.To = startOfDay(r.Points[len(r.Points)-1].CreateTime).AddDate(0, 0, 1)
+	}
+
+	var (
+		sum           float64
+		weekdaySum    [7]float64
+		weekdayCount  [7]int
+		monthIndex    = make(map[string]int)
+		monthSums     []float64
+		monthsInOrder []MonthlyMood
+	)
+	for _, p := range r.Points {
+		m := float64(p.Mood)
+		sum += m
+
+		wd := p.CreateTime.Weekday()
+		weekdaySum[wd] += m
+		weekdayCount[wd]++
+
+		t := p.CreateTime
+		month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		i, ok := monthIndex[month.Format("2006-01")]
+		if !ok {
+			i = len(monthsInOrder)
+			monthIndex[month.Format("2006-01")] = i
+			monthsInOrder = append(monthsInOrder, MonthlyMood{Month: month})
+			monthSums = append(monthSums, 0)
+		}
+		monthSums[i] += m
+		monthsInOrder[i].Count++
+	}
+
+	r.Average = sum / float64(len(r.Points))
+	for wd := range weekdaySum {
+		if weekdayCount[wd] > 0 {
+			r.ByWeekday[wd] = weekdaySum[wd] / float64(weekdayCount[wd])
+		}
+	}
+	for i := range monthsInOrder {
+		monthsInOrder[i].Average = monthSums[i] / float64(monthsInOrder[i].Count)
+	}
+	r.ByMonth = monthsInOrder
+
+	return r, nil
+}
+
+// Daily returns the average mood of each day from r.From to r.To, NaN for days without one.
+// If there are more than width days, consecutive days are averaged together to fit.
+func (r MoodReport) Daily(width int) []float64 {
+	if len(r.Points) == 0 || width <= 0 {
+		return nil
+	}
+
+	// days are keyed by date as the same day may be written down in different locations.
+	const layout = "2006-01-02"
+	loc := r.From.Location()
+	day := make(map[string]int)
+	var n int
+	for d := startOfDay(r.From); d.Before(r.To); d = d.AddDate(0, 0, 1) {
+		day[d.Format(layout)] = n
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	buckets := n
+	if buckets > width {
+		buckets = width
+	}
+
+	sums := make([]float64, buckets)
+	counts := make([]int, buckets)
+	for _, p := range r.Points {
+		i, ok := day[p.CreateTime.In(loc).Format(layout)]
+		if !ok {
+			continue
+		}
+		b := i * buckets / n
+		sums[b] += float64(p.Mood)
+		counts[b]++
+	}
+
+	values := make([]float64, buckets)
+	for i := range values {
+		values[i] = math.NaN()
+		if counts[i] > 0 {
+			values[i] = sums[i] / float64(counts[i])
+		}
+	}
+
+	return values
+}
+
+// sparks are the characters Sparkline draws with, lowest first.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values from lo to hi as a line of block characters. NaN values are gaps.
+func Sparkline(values []float64, lo, hi float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case hi <= lo:
+			b.WriteRune(sparks[len(sparks)/2])
+		default:
+			i := int(math.Round((v - lo) / (hi - lo) * float64(len(sparks)-1)))
+			if i < 0 {
+				i = 0
+			}
+			if i >= len(sparks) {
+				i = len(sparks) - 1
+			}
+			b.WriteRune(sparks[i])
+		}
+	}
+
+	return b.String()
+}
+
+// startOfDay returns midnight at the start of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
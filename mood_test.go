@@ -0,0 +1,103 @@
+package jrnl
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestJournal_SetMood(t *testing.T) {
+	j := mustNewTestJournal(t)
+	e := mustCreateEntry(t, j, "a good day")
+
+	got, err := j.SetMood(e.ID, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := j.GetEntry(e.ID); stored.Mood != 4 || got.Mood != 4 || stored.Content != "a good day" {
+		t.Errorf("SetMood() stored %+v", stored)
+	}
+
+	if _, err = j.SetMood(e.ID, len(DefaultMoodScale)+1); err == nil {
+		t.Errorf("expected a mood beyond the scale to be refused")
+	}
+	j.UseMoodScale(make([]MoodLevel, ScaleMax))
+	if _, err = j.SetMood(e.ID, ScaleMax); err != nil {
+		t.Errorf("SetMood() at the top of a longer scale: %v", err)
+	}
+	if _, err = j.SetMood(e.ID, ScaleMax+1); err == nil {
+		t.Errorf("expected a mood beyond the scale to be refused")
+	}
+	if _, err = j.SetMood(e.ID+1, 3); err == nil {
+		t.Errorf("expected a missing entry to be an error")
+	}
+	if got, err = j.SetMood(e.ID, 0); err != nil || got.Mood != 0 {
+		t.Errorf("SetMood() to clear = %+v, %v", got, err)
+	}
+}
+
+func TestJournal_MoodReport(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	// Monday the 30th of January to Thursday the 2nd of February.
+	monday := time.Date(2023, time.January, 30, 20, 0, 0, 0, time.UTC)
+	mustPutEntry(t, j, Entry{ID: 1, Mood: 2, CreateTime: monday})
+	mustPutEntry(t, j, Entry{ID: 2, Mood: 4, CreateTime: monday.Add(time.Hour)})
+	mustPutEntry(t, j, Entry{ID: 3, CreateTime: monday.AddDate(0, 0, 1)})
+	mustPutEntry(t, j, Entry{ID: 4, Mood: 5, CreateTime: monday.AddDate(0, 0, 2)})
+	mustPutEntry(t, j, Entry{ID: 5, Mood: 1, CreateTime: monday.AddDate(0, 0, 3)})
+
+	r, err := j.MoodReport(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Average != 3 || len(r.Points) != 4 || r.Points[0].EntryID != 1 {
+		t.Errorf("MoodReport() = %+v", r)
+	}
+	if !r.From.Equal(time.Date(2023, time.January, 30, 0, 0, 0, 0, time.UTC)) || !r.To.Equal(time.Date(2023, time.February, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("MoodReport() spans %v to %v", r.From, r.To)
+	}
+	if diff := cmp.Diff(r.ByWeekday, [7]float64{time.Monday: 3, time.Wednesday: 5, time.Thursday: 1}); diff != "" {
+		t.Errorf("MoodReport() ByWeekday (-got, +want):\n%s", diff)
+	}
+	wantMonths := []MonthlyMood{
+		{Month: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), Average: 3, Count: 2},
+		{Month: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC), Average: 3, Count: 2},
+	}
+	if diff := cmp.Diff(r.ByMonth, wantMonths); diff != "" {
+		t.Errorf("MoodReport() ByMonth (-got, +want):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(r.Daily(10), []float64{3, math.NaN(), 5, 1}, cmpopts.EquateNaNs()); diff != "" {
+		t.Errorf("Daily(10) (-got, +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(r.Daily(2), []float64{3, 3}); diff != "" {
+		t.Errorf("Daily(2) (-got, +want):\n%s", diff)
+	}
+	if got := Sparkline(r.Daily(10), 1, 5); got != "▅ █▁" {
+		t.Errorf("Sparkline() = %q", got)
+	}
+
+	r, err = j.MoodReport(monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Points) != 1 || r.Points[0].EntryID != 4 {
+		t.Errorf("MoodReport() with a span = %+v", r.Points)
+	}
+}
+
+func TestMoodConfig_MoodScale(t *testing.T) {
+	var c *MoodConfig
+	if got := c.MoodScale(); len(got) != len(DefaultMoodScale) {
+		t.Errorf("MoodScale() of no config = %v, want the default", got)
+	}
+
+	c = &MoodConfig{Scale: make([]MoodLevel, ScaleMax+2)}
+	if got := c.MoodScale(); len(got) != ScaleMax {
+		t.Errorf("MoodScale() has %d levels, want at most %d", len(got), ScaleMax)
+	}
+}
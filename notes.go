@@ -0,0 +1,331 @@
+package jrnl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// noteDate matches a date in a note's file name, 2023-01-05.md or Logseq's 2023_01_05.md.
+	noteDate = regexp.MustCompile(`(\d{4})[-_](\d{2})[-_](\d{2})`)
+
+	// noteImage matches a markdown image, ![alt](path "title"), or an Obsidian embed, ![[path|size]].
+	noteImage = regexp.MustCompile(`!\[([^\]]*)\]\(<?([^)>\s]+)>?(?:\s+"[^"]*")?\)|!\[\[([^\]|]+)(?:\|[^\]]*)?\]\]`)
+
+	// noteProperty matches a Logseq page property line, e.g. "tags:: work, ideas".
+	noteProperty = regexp.MustCompile(`^([A-Za-z][\w-]*):: ?(.*)$`)
+
+	// noteTimeLayouts are the date formats accepted in front matter, in local time unless they have an offset.
+	noteTimeLayouts = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+)
+
+// note is a markdown file being imported and the images it links to.
+type note struct {
+	entry  Entry
+	images map[string]string
+}
+
+// ImportMarkdownDir walks dir for markdown notes, such as an Obsidian vault or Logseq
+// journals folder, and adds each as an entry. A note's date comes from its front matter's
+// date or created field, a date in its file name like 2023-01-05.md, or failing those its
+// modification time. Front matter tags, title and starred become the entry's, and any
+// other front matter fields are kept in its metadata. Images the note links to inside dir
+// are imported as attachments. Hidden files and folders are ignored.
+func (j *Journal) ImportMarkdownDir(dir string, opts ImportOptions) (ImportResult, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	var (
+		notes []string
+		files = make(map[string][]string)
+	)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		if strings.EqualFold(filepath.Ext(path), ".md") {
+			notes = append(notes, path)
+		} else {
+			files[d.Name()] = append(files[d.Name()], path)
+		}
+		return nil
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	images := make(map[string]string)
+	next := func() (Entry, error) {
+		if len(notes) == 0 {
+			return Entry{}, io.EOF
+		}
+		path := notes[0]
+		notes = notes[1:]
+
+		n, rerr := readNote(root, path, files)
+		if rerr != nil {
+			return Entry{}, rerr
+		}
+		for id, p := range n.images {
+			images[id] = p
+		}
+		return n.entry, nil
+	}
+	readAttachment := func(id string) ([]byte, error) {
+		return os.ReadFile(images[id])
+	}
+
+	return j.importEntries(next, readAttachment, nil, opts, attachmentBatchSize)
+}
+
+// readNote reads the note at path, inside root, into an entry. files lists the other files
+// in root by name, for resolving Obsidian embeds that only give a file's name.
+func readNote(root, path string, files map[string][]string) (note, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return note{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return note{}, err
+	}
+
+	fields, body := parseFrontMatter(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	n := note{images: make(map[string]string)}
+	e := &n.entry
+
+	for _, key := range []string{"date", "created"} {
+		if t, ok := noteTime(fields[key]); ok && e.CreateTime.IsZero() {
+			e.CreateTime = t
+		}
+		delete(fields, key)
+	}
+	if e.CreateTime.IsZero() {
+		if m := noteDate.FindStringSubmatch(filepath.Base(path)); m != nil {
+			e.CreateTime, _ = time.ParseInLocation("2006-01-02", m[1]+"-"+m[2]+"-"+m[3], time.Local)
+		}
+	}
+	if e.CreateTime.IsZero() {
+		e.CreateTime = info.ModTime()
+	}
+	for _, key := range []string{"updated", "modified"} {
+		if t, ok := noteTime(fields[key]); ok && e.UpdateTime.IsZero() {
+			e.UpdateTime = t
+		}
+		delete(fields, key)
+	}
+
+	if title, ok := fields["title"].(string); ok {
+		e.Title = title
+	}
+	if starred, ok := fields["starred"].(string); ok {
+		e.Starred, _ = strconv.ParseBool(starred)
+	}
+	for _, tag := range noteList(fields["tags"]) {
+		*e = withTag(*e, strings.TrimPrefix(tag, "#"))
+	}
+	// id is written by ExportMarkdown and means nothing in another journal.
+	for _, key := range []string{"title", "starred", "tags", "id"} {
+		delete(fields, key)
+	}
+	if len(fields) > 0 {
+		e.Metadata = make(map[string]json.RawMessage, len(fields))
+		for k, v := range fields {
+			e.Metadata[k], _ = json.Marshal(v)
+		}
+	}
+
+	rel, _ := filepath.Rel(root, path)
+	e.Content = noteImage.ReplaceAllStringFunc(strings.TrimLeft(body, "\n"), func(m string) string {
+		parts := noteImage.FindStringSubmatch(m)
+		alt, link := parts[1], parts[2]
+		if parts[3] != "" {
+			alt, link = parts[3], parts[3]
+		}
+
+		file, ok := resolveNoteImage(root, filepath.Dir(path), link, files)
+		if !ok {
+			return m
+		}
+
+		// derived rather than random so importing the same notes twice finds the duplicates.
+		sum := sha256.Sum256([]byte(filepath.ToSlash(rel) + "\x00" + link))
+		a := Attachment{ID: hex.EncodeToString(sum[:16]), Name: filepath.Base(file), MediaType: mediaType(file)}
+		if _, seen := n.images[a.ID]; !seen {
+			e.Attachments = append(e.Attachments, a)
+			n.images[a.ID] = file
+		}
+		return "![" + alt + "](" + a.URL() + ")"
+	})
+
+	return n, nil
+}
+
+// resolveNoteImage finds the local image file a note in dir links to, link is either a path
+// relative to dir or, as Obsidian allows, just the name of a file somewhere in root.
+func resolveNoteImage(root, dir, link string, files map[string][]string) (string, bool) {
+	if strings.Contains(link, ":") {
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(link); err == nil {
+		link = unescaped
+	}
+	if !strings.HasPrefix(mediaType(link), "image/") {
+		return "", false
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(link))
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		if info, serr := os.Stat(path); serr == nil && info.Mode().IsRegular() {
+			return path, true
+		}
+	}
+	if found := files[filepath.Base(link)]; len(found) > 0 {
+		return found[0], true
+	}
+
+	return "", false
+}
+
+// parseFrontMatter splits YAML front matter, or Logseq page properties, off the top of a
+// note. It understands the subset of YAML notes use: scalar values, which are returned as
+// strings, and inline or block lists of scalars, which are returned as []string.
+func parseFrontMatter(text string) (map[string]interface{}, string) {
+	fields := make(map[string]interface{})
+	lines := strings.Split(text, "\n")
+
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		// Logseq keeps page properties as key:: value lines at the top of the page.
+		i := 0
+		for ; i < len(lines); i++ {
+			m := noteProperty.FindStringSubmatch(lines[i])
+			if m == nil {
+				break
+			}
+			fields[m[1]] = m[2]
+			if m[1] == "tags" || m[1] == "alias" {
+				fields[m[1]] = splitNoteList(m[2])
+			}
+		}
+		return fields, strings.Join(lines[i:], "\n")
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if t := strings.TrimSpace(lines[i]); t == "---" || t == "..." {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return fields, text
+	}
+
+	var key string
+	for _, line := range lines[1:end] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") && key != "" {
+			list, _ := fields[key].([]string)
+			fields[key] = append(list, yamlScalar(strings.TrimPrefix(trimmed, "- ")))
+			continue
+		}
+
+		k, v, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") {
+			continue
+		}
+		key = strings.TrimSpace(k)
+		v = strings.TrimSpace(v)
+
+		switch {
+		case v == "":
+			fields[key] = []string{}
+		case strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]"):
+			fields[key] = splitNoteList(v[1 : len(v)-1])
+		default:
+			fields[key] = yamlScalar(v)
+		}
+	}
+
+	return fields, strings.Join(lines[end+1:], "\n")
+}
+
+func splitNoteList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = yamlScalar(strings.TrimSpace(item)); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// yamlScalar unquotes a YAML scalar.
+func yamlScalar(s string) string {
+	switch {
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			return unquoted
+		}
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+// noteList returns a front matter value as a list, a single string is split on commas and spaces.
+func noteList(v interface{}) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	return nil
+}
+
+func noteTime(v interface{}) (time.Time, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range noteTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,130 @@
+package jrnl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_ImportMarkdownDir(t *testing.T) {
+	dir := t.TempDir()
+	mtime := time.Date(2022, time.June, 1, 12, 0, 0, 0, time.Local)
+
+	for name, content := range map[string]string{
+		"daily/2023-01-05.md": "---\ntags: [work, \"deep focus\"]\nmood: good\n---\n\nShipped it.\n\n![chart](../assets/chart.png)\n",
+		"daily/2023_01_06.md": "tags:: home, cooking\n\nMade bread. ![[bread.jpg|300]]\n",
+		"ideas.md":            "---\ntitle: Ideas\ncreated: 2023-01-07 21:15\ntags:\n  - someday\n  - \"#big\"\nstarred: true\n---\nA garden.\n\n![missing](nowhere.png) ![remote](https://example.com/x.png)\n",
+		"undated.md":          "no date anywhere",
+		"assets/chart.png":    "png bytes",
+		"photos/bread.jpg":    "jpg bytes",
+		".obsidian/notes.md":  "settings, not a note",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	j := mustNewTestJournal(t)
+
+	var previewed []Entry
+	res, err := j.ImportMarkdownDir(dir, ImportOptions{DryRun: true, Preview: func(e Entry) {
+		previewed = append(previewed, e)
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res, ImportResult{Imported: 4}); diff != "" {
+		t.Errorf("ImportMarkdownDir() preview (-got, +want):\n%s", diff)
+	}
+	if len(previewed) != 4 {
+		t.Errorf("expected 4 entries previewed, got %d", len(previewed))
+	}
+	if entries, _ := j.ListEntries(); len(entries) != 0 {
+		t.Fatalf("preview wrote %d entries", len(entries))
+	}
+
+	if _, err = j.ImportMarkdownDir(dir, ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := j.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byDay := make(map[string]Entry)
+	for _, e := range entries {
+		byDay[e.CreateTime.Format("2006-01-02")] = e
+	}
+
+	focus := byDay["2023-01-05"]
+	if !cmp.Equal(focus.Tags, []string{"work", "deep focus"}) || string(focus.Metadata["mood"]) != `"good"` {
+		t.Errorf("expected front matter tags and metadata, got %+v", focus)
+	}
+	if len(focus.Attachments) != 1 || !strings.Contains(focus.Content, "![chart]("+focus.Attachments[0].URL()+")") {
+		t.Fatalf("expected the chart to be attached, got %+v", focus)
+	}
+	if data, _ := j.ReadAttachment(focus.Attachments[0].ID); string(data) != "png bytes" {
+		t.Errorf("chart attachment = %q", data)
+	}
+
+	bread := byDay["2023-01-06"]
+	if !cmp.Equal(bread.Tags, []string{"home", "cooking"}) || len(bread.Attachments) != 1 || bread.Attachments[0].Name != "bread.jpg" {
+		t.Errorf("expected Logseq properties and the embedded photo, got %+v", bread)
+	}
+
+	ideas := byDay["2023-01-07"]
+	if ideas.Title != "Ideas" || !ideas.Starred || ideas.CreateTime.Hour() != 21 || !cmp.Equal(ideas.Tags, []string{"someday", "big"}) {
+		t.Errorf("expected title, starred, time and tags from front matter, got %+v", ideas)
+	}
+	if len(ideas.Attachments) != 0 || !strings.Contains(ideas.Content, "![missing](nowhere.png)") {
+		t.Errorf("links to missing or remote images should be left alone, got %+v", ideas)
+	}
+
+	if undated, ok := byDay["2022-06-01"]; !ok || undated.Content != "no date anywhere" {
+		t.Errorf("expected the undated note to use its modification time, got %+v", entries)
+	}
+
+	res, err = j.ImportMarkdownDir(dir, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res, ImportResult{Duplicates: 4}); diff != "" {
+		t.Errorf("re-import (-got, +want):\n%s", diff)
+	}
+}
+
+func TestJournal_ImportMarkdownDir_RoundTrip(t *testing.T) {
+	src := mustNewTestJournal(t)
+
+	day := time.Date(2023, time.January, 5, 9, 30, 0, 0, time.UTC)
+	mustPutEntry(t, src, Entry{ID: 1, Content: "evening thoughts\n", CreateTime: day, UpdateTime: day.Add(time.Hour), Title: `A "long" day`, Starred: true, Tags: []string{"work", "home"}})
+
+	dir := t.TempDir()
+	if _, err := src.ExportMarkdown(dir, MarkdownExportOptions{GroupByMonth: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := mustNewTestJournal(t)
+	if _, err := dst.ImportMarkdownDir(dir, ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := src.ListEntries()
+	got, err := dst.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+		t.Errorf("round trip (-got, +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,38 @@
+package jrnl
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// Heading is a heading of a markdown document.
+type Heading struct {
+	// Level is 1 for a top level heading, 2 for the ones below it and so on.
+	Level int
+	Text  string
+}
+
+// Outline returns the headings of the markdown document md in the order they appear. Lines
+// that only look like headings, such as those in code blocks, are left out.
+func Outline(md string) []Heading {
+	source := []byte(md)
+	doc := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser().Parse(text.NewReader(source))
+
+	var headings []Heading
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		if t := strings.TrimSpace(string(h.Text(source))); t != "" {
+			headings = append(headings, Heading{Level: h.Level, Text: t})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+
+	return headings
+}
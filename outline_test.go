@@ -0,0 +1,25 @@
+package jrnl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOutline(t *testing.T) {
+	md := "# Trip\n\nWe left early.\n\n## Day one\n\n```sh\n# not a heading\n```\n\nThe *first* day\n---\n\n### Dinner at **Luigi's**\n"
+
+	want := []Heading{
+		{Level: 1, Text: "Trip"},
+		{Level: 2, Text: "Day one"},
+		{Level: 2, Text: "The first day"},
+		{Level: 3, Text: "Dinner at Luigi's"},
+	}
+	if diff := cmp.Diff(Outline(md), want); diff != "" {
+		t.Errorf("Outline() (-got, +want):\n%s", diff)
+	}
+
+	if got := Outline("no headings here"); len(got) != 0 {
+		t.Errorf("Outline() = %v, want none", got)
+	}
+}
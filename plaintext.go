@@ -0,0 +1,70 @@
+package jrnl
+
+import (
+	"crypto/rand"
+	"io"
+	"os"
+)
+
+// TempPlaintextFile writes content to a new file only the user can read and returns its path.
+// The file is created on a memory backed filesystem, $XDG_RUNTIME_DIR or /dev/shm, where
+// there is one so the plaintext never reaches the disk. pattern names the file as in
+// os.CreateTemp. The file should be removed with ShredFile as soon as it isn't needed.
+func TempPlaintextFile(pattern, content string) (string, error) {
+	var (
+		f   *os.File
+		err error
+	)
+	for _, dir := range []string{os.Getenv("XDG_RUNTIME_DIR"), "/dev/shm", os.TempDir()} {
+		if dir == "" {
+			continue
+		}
+		if f, err = os.CreateTemp(dir, pattern); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// CreateTemp already uses 0600, this guards against it ever changing.
+	err = f.Chmod(0600)
+	if err == nil {
+		_, err = io.WriteString(f, content)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = ShredFile(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// ShredFile overwrites the file at path with random data before removing it, so its
+// plaintext can't be read back from the freed blocks. Copy on write filesystems and SSDs
+// may still keep old copies of the data, this is a best effort.
+func ShredFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err == nil {
+		_, err = io.CopyN(f, rand.Reader, info.Size())
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
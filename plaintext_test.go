@@ -0,0 +1,53 @@
+package jrnl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTempPlaintextFile(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	path, err := TempPlaintextFile("jrnl-*.md", "dear diary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != os.Getenv("XDG_RUNTIME_DIR") || filepath.Ext(path) != ".md" {
+		t.Errorf("expected a .md file in the runtime dir, got %s", path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected permissions 0600, got %o", perm)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "dear diary" {
+		t.Errorf("content = %q", data)
+	}
+
+	if err = ShredFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", path, err)
+	}
+}
+
+func TestTempPlaintextFile_Fallback(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", filepath.Join(t.TempDir(), "missing"))
+
+	path, err := TempPlaintextFile("jrnl-*.md", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = ShredFile(path)
+	}()
+
+	if filepath.Dir(path) == os.Getenv("XDG_RUNTIME_DIR") {
+		t.Errorf("expected a fallback directory, got %s", path)
+	}
+}
@@ -0,0 +1,142 @@
+package jrnl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestS3Store(t *testing.T) {
+	srv := newFakeS3(t, "journal-bucket")
+	t.Cleanup(srv.Close)
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:        srv.URL,
+		Bucket:          "journal-bucket",
+		Prefix:          "me/",
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if _, err = store.Get(ctx, "missing"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, ErrObjectNotFound)
+	}
+
+	if err = store.Put(ctx, "entries/abc", []byte("ciphertext")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.objects["/journal-bucket/me/entries/abc"]; !ok {
+		t.Errorf("Put() stored %v, want key under bucket and prefix", srv.objects)
+	}
+
+	got, err := store.Get(ctx, "entries/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "ciphertext" {
+		t.Errorf("Get() = %q, want %q", got, "ciphertext")
+	}
+
+	if err = store.Delete(ctx, "entries/abc"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Get(ctx, "entries/abc"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want %v", err, ErrObjectNotFound)
+	}
+}
+
+func TestS3Store_Sync(t *testing.T) {
+	srv := newFakeS3(t, "journal-bucket")
+	t.Cleanup(srv.Close)
+
+	cfg := S3Config{
+		Endpoint:        srv.URL,
+		Bucket:          "journal-bucket",
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+	}
+	a, err := NewS3Store(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewS3Store(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	laptop := mustNewSyncedJournal(t, a)
+	desktop := mustNewSyncedJournal(t, b)
+
+	mustCreateEntry(t, laptop, "synced through s3")
+	mustPush(t, laptop)
+	mustPull(t, desktop)
+
+	entries, err := desktop.ListEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Content != "synced through s3" {
+		t.Errorf("ListEntries() = %+v, want the synced entry", entries)
+	}
+}
+
+// fakeS3 is a tiny stand-in for an S3-compatible server such as MinIO.
+type fakeS3 struct {
+	*httptest.Server
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3(tb testing.TB, bucket string) *fakeS3 {
+	tb.Helper()
+
+	s := &fakeS3{objects: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, s3Algorithm+" Credential=minioadmin/") || r.Header.Get("X-Amz-Date") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/"+bucket+"/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := s.objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil || sha256Hex(data) != r.Header.Get("X-Amz-Content-Sha256") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			s.objects[r.URL.Path] = data
+		case http.MethodDelete:
+			delete(s.objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+
+	return s
+}
@@ -0,0 +1,509 @@
+package jrnl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrSchemaNotFound is returned when there is no schema with the given name.
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// FieldType is the kind of value a field holds.
+type FieldType string
+
+// The field types. Values are kept as text in a canonical form, shown after each type.
+const (
+	// FieldNumber is any number, "5.2".
+	FieldNumber FieldType = "number"
+	// FieldScale is a whole number from ScaleMin to ScaleMax, "7".
+	FieldScale FieldType = "scale"
+	// FieldEnum is one of the field's options, "run".
+	FieldEnum FieldType = "enum"
+	// FieldBool is "true" or "false", yes/no, y/n and 1/0 are accepted too.
+	FieldBool FieldType = "bool"
+	// FieldDuration is a Go duration, "1h30m0s", or a number of minutes.
+	FieldDuration FieldType = "duration"
+)
+
+// The range of a FieldScale value.
+const (
+	ScaleMin = 1
+	ScaleMax = 10
+)
+
+// Field is one typed field of a Schema.
+type Field struct {
+	Name string    `json:"name"`
+	Type FieldType `json:"type"`
+	// Options are the values an enum field may take.
+	Options []string `json:"options,omitempty"`
+}
+
+// Parse checks that s is a value of f and returns it in canonical form.
+func (f Field) Parse(s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	switch f.Type {
+	case FieldNumber:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return "", fmt.Errorf("%s: %q is not a number", f.Name, s)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case FieldScale:
+		n, err := strconv.Atoi(s)
+		if err != nil || n < ScaleMin || n > ScaleMax {
+			return "", fmt.Errorf("%s: %q is not a whole number from %d to %d", f.Name, s, ScaleMin, ScaleMax)
+		}
+		return strconv.Itoa(n), nil
+	case FieldEnum:
+		for _, o := range f.Options {
+			if strings.EqualFold(o, s) {
+				return o, nil
+			}
+		}
+		return "", fmt.Errorf("%s: %q is not one of %s", f.Name, s, strings.Join(f.Options, ", "))
+	case FieldBool:
+		switch strings.ToLower(s) {
+		case "true", "yes", "y", "1":
+			return "true", nil
+		case "false", "no", "n", "0":
+			return "false", nil
+		}
+		return "", fmt.Errorf("%s: %q is not yes or no", f.Name, s)
+	case FieldDuration:
+		if minutes, err := strconv.ParseFloat(s, 64); err == nil && minutes >= 0 {
+			return time.Duration(minutes * float64(time.Minute)).String(), nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return "", fmt.Errorf("%s: %q is not a duration like 1h30m", f.Name, s)
+		}
+		return d.String(), nil
+	}
+
+	return "", fmt.Errorf("%s: unknown field type %q", f.Name, f.Type)
+}
+
+// Float returns the value s of f as a number to chart or compare: numbers and scales as they
+// are, durations in minutes, booleans as 1 or 0 and enums as the index of the option.
+func (f Field) Float(s string) (float64, error) {
+	v, err := f.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+
+	switch f.Type {
+	case FieldDuration:
+		d, derr := time.ParseDuration(v)
+		return d.Minutes(), derr
+	case FieldBool:
+		if v == "true" {
+			return 1, nil
+		}
+		return 0, nil
+	case FieldEnum:
+		for i, o := range f.Options {
+			if o == v {
+				return float64(i), nil
+			}
+		}
+	}
+
+	return strconv.ParseFloat(v, 64)
+}
+
+// Hint describes the values f takes, for prompts and help text.
+func (f Field) Hint() string {
+	switch f.Type {
+	case FieldScale:
+		return fmt.Sprintf("%d-%d", ScaleMin, ScaleMax)
+	case FieldEnum:
+		return strings.Join(f.Options, "|")
+	case FieldBool:
+		return "yes|no"
+	case FieldDuration:
+		return "e.g. 1h30m"
+	}
+	return string(f.Type)
+}
+
+// Schema is a named set of typed fields entries of one kind share, e.g. a workout with a
+// distance, a duration and the kind of exercise. An entry follows at most one schema.
+//
+// jrnl has no notebooks, so schemas belong to the journal as a whole rather than to a
+// notebook: every schema is offered for every entry, and its name has to be unique in the
+// journal. Keeping kinds of entries apart takes a schema per kind.
+//
+// Schemas are written as JSON, the name is given separately:
+//
+//	{
+//	  "fields": [
+//	    {"name": "kind", "type": "enum", "options": ["run", "swim", "lift"]},
+//	    {"name": "distance", "type": "number"},
+//	    {"name": "time", "type": "duration"},
+//	    {"name": "effort", "type": "scale"},
+//	    {"name": "injured", "type": "bool"}
+//	  ]
+//	}
+type Schema struct {
+	Name   string  `json:"name,omitempty"`
+	Fields []Field `json:"fields"`
+}
+
+// Validate checks that s has a usable name and well formed fields.
+func (s Schema) Validate() error {
+	if !templateNameChars.MatchString(s.Name) {
+		return fmt.Errorf("invalid schema name %q: use letters, digits, - and _", s.Name)
+	}
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("schema %s has no fields", s.Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range s.Fields {
+		switch {
+		case strings.TrimSpace(f.Name) == "" || strings.ContainsAny(f.Name, "=<>!"):
+			return fmt.Errorf("schema %s: invalid field name %q", s.Name, f.Name)
+		case seen[f.Name]:
+			return fmt.Errorf("schema %s: field %s is defined twice", s.Name, f.Name)
+		case f.Type == FieldEnum && len(f.Options) == 0:
+			return fmt.Errorf("schema %s: enum field %s has no options", s.Name, f.Name)
+		}
+		switch f.Type {
+		case FieldNumber, FieldScale, FieldEnum, FieldBool, FieldDuration:
+		default:
+			return fmt.Errorf("schema %s: field %s has unknown type %q", s.Name, f.Name, f.Type)
+		}
+		seen[f.Name] = true
+	}
+
+	return nil
+}
+
+// Field returns the field of s called name.
+func (s Schema) Field(name string) (Field, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Parse checks values against the fields of s and returns them in canonical form. Empty values
+// are left out, fields are optional.
+func (s Schema) Parse(values map[string]string) (map[string]string, error) {
+	parsed := make(map[string]string, len(values))
+	for name, v := range values {
+		f, ok := s.Field(name)
+		if !ok {
+			return nil, fmt.Errorf("schema %s has no field %s", s.Name, name)
+		}
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		p, err := f.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		parsed[name] = p
+	}
+
+	return parsed, nil
+}
+
+// SaveSchema stores s encrypted in the journal, replacing any schema with the same name.
+// Entries keep the values they have, those of fields s no longer has are ignored.
+func (j *Journal) SaveSchema(s Schema) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	encrypted, err := encrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return err
+	}
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(schemaBucketName)).Put([]byte(s.Name), encrypted)
+	})
+}
+
+// DeleteSchema removes the schema called name. Entries following it keep their values.
+func (j *Journal) DeleteSchema(name string) error {
+	return j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(schemaBucketName))
+		if b.Get([]byte(name)) == nil {
+			return fmt.Errorf("%w: %s", ErrSchemaNotFound, name)
+		}
+		return b.Delete([]byte(name))
+	})
+}
+
+// GetSchema returns the schema called name.
+func (j *Journal) GetSchema(name string) (Schema, error) {
+	var s Schema
+	err := j.db.View(func(tx *bolt.Tx) error {
+		var err error
+		s, err = j.getSchema(tx.Bucket([]byte(schemaBucketName)), name)
+		return err
+	})
+
+	return s, err
+}
+
+// ListSchemas lists the schemas in the journal sorted by name.
+func (j *Journal) ListSchemas() ([]Schema, error) {
+	schemas := make([]Schema, 0)
+	err := j.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(schemaBucketName))
+		return b.ForEach(func(k, _ []byte) error {
+			s, err := j.getSchema(b, string(k))
+			if err != nil {
+				return err
+			}
+			schemas = append(schemas, s)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Name < schemas[j].Name
+	})
+
+	return schemas, nil
+}
+
+func (j *Journal) getSchema(b *bolt.Bucket, name string) (Schema, error) {
+	data := b.Get([]byte(name))
+	if data == nil {
+		return Schema{}, fmt.Errorf("%w: %s", ErrSchemaNotFound, name)
+	}
+
+	decrypted, err := decrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return Schema{}, err
+	}
+
+	var s Schema
+	if err = json.Unmarshal(decrypted, &s); err != nil {
+		return Schema{}, fmt.Errorf("schema %s: %w", name, err)
+	}
+	s.Name = name
+
+	return s, nil
+}
+
+// SetEntryFields sets the schema entry id follows and the values of its fields, replacing
+// any it had. An empty schema removes the entry's fields.
+func (j *Journal) SetEntryFields(id int, schema string, values map[string]string) (Entry, error) {
+	var e Entry
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(journalBucketName))
+
+		var err error
+		if e, err = j.getEntry(b, id); err != nil {
+			return err
+		}
+		if e.Schema, e.Fields, err = j.parseFields(tx, schema, values); err != nil {
+			return err
+		}
+		e.UpdateTime = time.Now()
+
+		return j.putEntry(b, e)
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if err = j.recordGitHistory("Edit", e.ID); err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
+
+// parseFields checks values against the schema called name.
+func (j *Journal) parseFields(tx *bolt.Tx, name string, values map[string]string) (string, map[string]string, error) {
+	if name == "" {
+		return "", nil, nil
+	}
+
+	s, err := j.getSchema(tx.Bucket([]byte(schemaBucketName)), name)
+	if err != nil {
+		return "", nil, err
+	}
+	parsed, err := s.Parse(values)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(parsed) == 0 {
+		parsed = nil
+	}
+
+	return name, parsed, nil
+}
+
+// FieldPoint is the value of a field in one entry.
+type FieldPoint struct {
+	EntryID    int
+	CreateTime time.Time
+	// Value is the value as stored, Number is the value as Field.Float returns it.
+	Value  string
+	Number float64
+}
+
+// FieldValues returns the values field has in the entries following schema, oldest first,
+// e.g. to chart them. Entries without a value for field are left out, as are values that
+// no longer parse because the field's type changed since they were written.
+func (j *Journal) FieldValues(schema, field string) ([]FieldPoint, error) {
+	s, err := j.GetSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := s.Field(field)
+	if !ok {
+		return nil, fmt.Errorf("schema %s has no field %s", schema, field)
+	}
+
+	points := make([]FieldPoint, 0)
+	err = j.forEachEntry(func(e Entry) error {
+		v, ok := e.Fields[field]
+		if e.Schema != schema || !ok {
+			return nil
+		}
+		n, ferr := f.Float(v)
+		if ferr != nil {
+			return nil
+		}
+		points = append(points, FieldPoint{EntryID: e.ID, CreateTime: e.CreateTime, Value: v, Number: n})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].CreateTime.Before(points[j].CreateTime)
+	})
+
+	return points, nil
+}
+
+// fieldOps are the comparisons a FieldFilter can make, longest first so "<=" isn't read as "<".
+var fieldOps = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// FieldFilter compares a field of an entry with a value, e.g. distance>=5 or kind=run.
+type FieldFilter struct {
+	Field string
+	// Op is one of =, !=, <, <=, > and >=. Ordering compares the values as Field.Float returns them.
+	Op    string
+	Value string
+}
+
+// ParseFieldFilter reads a filter written as field, op and value, "effort>=7".
+func ParseFieldFilter(s string) (FieldFilter, error) {
+	for _, op := range fieldOps {
+		if i := strings.Index(s, op); i > 0 {
+			return FieldFilter{
+				Field: strings.TrimSpace(s[:i]),
+				Op:    op,
+				Value: strings.TrimSpace(s[i+len(op):]),
+			}, nil
+		}
+	}
+
+	return FieldFilter{}, fmt.Errorf("invalid field filter %q: want a field, one of %s and a value", s, strings.Join(fieldOps, " "))
+}
+
+// match reports whether e, which follows s, passes the filter. An entry without a value for
+// the field only passes !=, as does one whose value can't be ordered because the field's type
+// changed since it was written.
+func (ff FieldFilter) match(s Schema, e Entry) (bool, error) {
+	f, ok := s.Field(ff.Field)
+	if !ok {
+		return false, fmt.Errorf("schema %s has no field %s", s.Name, ff.Field)
+	}
+	want, err := f.Parse(ff.Value)
+	if err != nil {
+		return false, err
+	}
+	v, ok := e.Fields[ff.Field]
+	if !ok {
+		return ff.Op == "!=", nil
+	}
+
+	switch ff.Op {
+	case "=":
+		return v == want, nil
+	case "!=":
+		return v != want, nil
+	}
+
+	a, err := f.Float(v)
+	if err != nil {
+		return false, nil
+	}
+	b, _ := f.Float(want)
+	switch ff.Op {
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	}
+
+	return false, fmt.Errorf("invalid field filter operator %q", ff.Op)
+}
+
+// FilterEntries lists the entries following schema that pass all of filters, newest first
+// like ListEntries.
+func (j *Journal) FilterEntries(schema string, filters ...FieldFilter) ([]Entry, error) {
+	s, err := j.GetSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0)
+	err = j.forEachEntry(func(e Entry) error {
+		if e.Schema != schema {
+			return nil
+		}
+		for _, ff := range filters {
+			ok, merr := ff.match(s, e)
+			if merr != nil || !ok {
+				return merr
+			}
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID > entries[j].ID
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,237 @@
+package jrnl
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bolt "go.etcd.io/bbolt"
+)
+
+var workoutSchema = Schema{
+	Name: "workout",
+	Fields: []Field{
+		{Name: "kind", Type: FieldEnum, Options: []string{"run", "swim", "lift"}},
+		{Name: "distance", Type: FieldNumber},
+		{Name: "time", Type: FieldDuration},
+		{Name: "effort", Type: FieldScale},
+		{Name: "injured", Type: FieldBool},
+	},
+}
+
+func TestField_Parse(t *testing.T) {
+	tests := []struct {
+		field   Field
+		in      string
+		want    string
+		wantNum float64
+		wantErr bool
+	}{
+		{field: workoutSchema.Fields[0], in: " Swim ", want: "swim", wantNum: 1},
+		{field: workoutSchema.Fields[0], in: "cycle", wantErr: true},
+		{field: workoutSchema.Fields[1], in: "05.50", want: "5.5", wantNum: 5.5},
+		{field: workoutSchema.Fields[1], in: "far", wantErr: true},
+		{field: workoutSchema.Fields[1], in: "NaN", wantErr: true},
+		{field: workoutSchema.Fields[2], in: "1h30m", want: "1h30m0s", wantNum: 90},
+		{field: workoutSchema.Fields[2], in: "45", want: "45m0s", wantNum: 45},
+		{field: workoutSchema.Fields[2], in: "-5m", wantErr: true},
+		{field: workoutSchema.Fields[3], in: "10", want: "10", wantNum: 10},
+		{field: workoutSchema.Fields[3], in: "11", wantErr: true},
+		{field: workoutSchema.Fields[3], in: "7.5", wantErr: true},
+		{field: workoutSchema.Fields[4], in: "Yes", want: "true", wantNum: 1},
+		{field: workoutSchema.Fields[4], in: "n", want: "false", wantNum: 0},
+		{field: workoutSchema.Fields[4], in: "maybe", wantErr: true},
+		{field: Field{Name: "x", Type: "colour"}, in: "red", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := tt.field.Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s.Parse(%q) error = %v, wantErr %v", tt.field.Name, tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s.Parse(%q) = %q, want %q", tt.field.Name, tt.in, got, tt.want)
+		}
+		if tt.wantErr {
+			continue
+		}
+		if n, _ := tt.field.Float(tt.in); n != tt.wantNum {
+			t.Errorf("%s.Float(%q) = %v, want %v", tt.field.Name, tt.in, n, tt.wantNum)
+		}
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema Schema
+	}{
+		{name: "bad name", schema: Schema{Name: "work out", Fields: workoutSchema.Fields}},
+		{name: "no fields", schema: Schema{Name: "empty"}},
+		{name: "duplicate field", schema: Schema{Name: "dup", Fields: []Field{{Name: "a", Type: FieldBool}, {Name: "a", Type: FieldNumber}}}},
+		{name: "enum without options", schema: Schema{Name: "enum", Fields: []Field{{Name: "a", Type: FieldEnum}}}},
+		{name: "operator in field name", schema: Schema{Name: "op", Fields: []Field{{Name: "a>b", Type: FieldBool}}}},
+		{name: "unknown type", schema: Schema{Name: "type", Fields: []Field{{Name: "a", Type: "text"}}}},
+	}
+	for _, tt := range tests {
+		if err := tt.schema.Validate(); err == nil {
+			t.Errorf("%s: expected Validate() to fail", tt.name)
+		}
+	}
+	if err := workoutSchema.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestJournal_SaveSchema(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	dream := Schema{Name: "dream", Fields: []Field{{Name: "lucid", Type: FieldBool}}}
+	for _, s := range []Schema{workoutSchema, dream} {
+		if err := j.SaveSchema(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.SaveSchema(Schema{Name: "../bad", Fields: dream.Fields}); err == nil {
+		t.Errorf("expected an invalid schema to be refused")
+	}
+
+	schemas, err := j.ListSchemas()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(schemas, []Schema{dream, workoutSchema}); diff != "" {
+		t.Errorf("ListSchemas() (-got, +want):\n%s", diff)
+	}
+
+	err = j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(schemaBucketName)).ForEach(func(_, v []byte) error {
+			if bytes.Contains(v, []byte("lucid")) {
+				t.Errorf("schema stored in plaintext")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = j.DeleteSchema("dream"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = j.GetSchema("dream"); !errors.Is(err, ErrSchemaNotFound) {
+		t.Errorf("GetSchema() of a deleted schema = %v, want ErrSchemaNotFound", err)
+	}
+	if err = j.DeleteSchema("dream"); !errors.Is(err, ErrSchemaNotFound) {
+		t.Errorf("DeleteSchema() of a missing schema = %v, want ErrSchemaNotFound", err)
+	}
+}
+
+func TestJournal_EntryFields(t *testing.T) {
+	j := mustNewTestJournal(t)
+	if err := j.SaveSchema(workoutSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := j.CreateEntryWithFields("easy run", "workout", map[string]string{"kind": "Run", "distance": "5", "time": "30", "effort": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(run.Fields, map[string]string{"kind": "run", "distance": "5", "time": "30m0s"}); diff != "" {
+		t.Errorf("CreateEntryWithFields() fields (-got, +want):\n%s", diff)
+	}
+	if _, err = j.CreateEntryWithFields("x", "workout", map[string]string{"pace": "fast"}); err == nil {
+		t.Errorf("expected a field the schema doesn't have to be refused")
+	}
+	if _, err = j.CreateEntryWithFields("x", "sleep", nil); !errors.Is(err, ErrSchemaNotFound) {
+		t.Errorf("CreateEntryWithFields() with a missing schema = %v, want ErrSchemaNotFound", err)
+	}
+
+	long, err := j.CreateEntryWithFields("long run", "workout", map[string]string{"kind": "run", "distance": "21.1", "effort": "9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	swim, err := j.CreateEntryWithFields("laps", "workout", map[string]string{"kind": "swim", "distance": "1.5", "effort": "6"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := mustCreateEntry(t, j, "no fields")
+
+	if plain, err = j.SetEntryFields(plain.ID, "workout", map[string]string{"kind": "lift", "effort": "8"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := j.GetEntry(plain.ID); got.Schema != "workout" || got.Fields["effort"] != "8" || got.Content != "no fields" {
+		t.Errorf("SetEntryFields() stored %+v", got)
+	}
+	if _, err = j.SetEntryFields(plain.ID, "workout", map[string]string{"effort": "12"}); err == nil {
+		t.Errorf("expected an invalid value to be refused")
+	}
+
+	points, err := j.FieldValues("workout", "distance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var distances []float64
+	for _, p := range points {
+		distances = append(distances, p.Number)
+	}
+	if diff := cmp.Diff(distances, []float64{5, 21.1, 1.5}); diff != "" {
+		t.Errorf("FieldValues() (-got, +want):\n%s", diff)
+	}
+
+	tests := []struct {
+		filters []string
+		want    []int
+	}{
+		{filters: nil, want: []int{plain.ID, swim.ID, long.ID, run.ID}},
+		{filters: []string{"kind=RUN"}, want: []int{long.ID, run.ID}},
+		{filters: []string{"effort>=8"}, want: []int{plain.ID, long.ID}},
+		{filters: []string{"kind!=run", "distance<2"}, want: []int{swim.ID}},
+		{filters: []string{"effort!=9"}, want: []int{plain.ID, swim.ID, run.ID}},
+	}
+	for _, tt := range tests {
+		var filters []FieldFilter
+		for _, s := range tt.filters {
+			ff, ferr := ParseFieldFilter(s)
+			if ferr != nil {
+				t.Fatal(ferr)
+			}
+			filters = append(filters, ff)
+		}
+		entries, ferr := j.FilterEntries("workout", filters...)
+		if ferr != nil {
+			t.Fatal(ferr)
+		}
+		var ids []int
+		for _, e := range entries {
+			ids = append(ids, e.ID)
+		}
+		if diff := cmp.Diff(ids, tt.want); diff != "" {
+			t.Errorf("FilterEntries(%v) (-got, +want):\n%s", tt.filters, diff)
+		}
+	}
+
+	if _, err = ParseFieldFilter("effort"); err == nil {
+		t.Errorf("expected a filter without an operator to be refused")
+	}
+	if _, err = j.FilterEntries("workout", FieldFilter{Field: "effort", Op: ">", Value: "high"}); err == nil {
+		t.Errorf("expected a filter with an invalid value to be refused")
+	}
+
+	// values written before a field's type changed are passed over rather than failing.
+	changed := Schema{Name: "workout", Fields: append([]Field{{Name: "kind", Type: FieldNumber}}, workoutSchema.Fields[1:]...)}
+	if err = j.SaveSchema(changed); err != nil {
+		t.Fatal(err)
+	}
+	if points, err = j.FieldValues("workout", "kind"); err != nil || len(points) != 0 {
+		t.Errorf("FieldValues() after the type changed = %+v, %v, want no values", points, err)
+	}
+	if entries, ferr := j.FilterEntries("workout", FieldFilter{Field: "kind", Op: ">=", Value: "0"}); ferr != nil || len(entries) != 0 {
+		t.Errorf("FilterEntries() after the type changed = %+v, %v, want no entries", entries, ferr)
+	}
+
+	if plain, err = j.SetEntryFields(plain.ID, "", nil); err != nil || plain.Schema != "" || plain.Fields != nil {
+		t.Errorf("SetEntryFields() to clear = %+v, %v", plain, err)
+	}
+}
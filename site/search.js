@@ -0,0 +1,54 @@
+// Client side search over the index in search-index.js. Everything runs locally so the
+// site works when opened straight from disk.
+(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var entries = document.getElementById("entries");
+  var index = window.jrnlSearchIndex || [];
+  var root = document.querySelector('link[rel="stylesheet"]').getAttribute("href").replace("assets/style.css", "");
+
+  function snippet(text, term) {
+    var at = text.toLowerCase().indexOf(term);
+    var start = Math.max(0, at - 60);
+    var s = (start > 0 ? "…" : "") + text.substr(start, 160);
+    return s;
+  }
+
+  function render(matches, terms) {
+    results.textContent = "";
+    matches.slice(0, 100).forEach(function (item) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = root + item.url;
+      a.textContent = item.title;
+      var p = document.createElement("p");
+      p.textContent = snippet(item.text, terms[0]);
+      li.appendChild(a);
+      li.appendChild(p);
+      results.appendChild(li);
+    });
+    if (matches.length === 0) {
+      var empty = document.createElement("li");
+      empty.textContent = "No entries found.";
+      results.appendChild(empty);
+    }
+  }
+
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    if (terms.length === 0) {
+      results.hidden = true;
+      entries.hidden = false;
+      return;
+    }
+
+    var matches = index.filter(function (item) {
+      var haystack = (item.title + " " + item.text).toLowerCase();
+      return terms.every(function (t) { return haystack.indexOf(t) !== -1; });
+    });
+
+    render(matches, terms);
+    results.hidden = false;
+    entries.hidden = true;
+  });
+})();
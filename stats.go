@@ -0,0 +1,231 @@
+package jrnl
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// entryStats is what Stats and MoodReport need to know about an entry. It is kept up to date as entries are
+// written so the stats never need the entries themselves decrypted.
+type entryStats struct {
+	CreateTime time.Time
+	Words      int
+	Mood       int `json:",omitempty"`
+}
+
+// DailyStats counts the writing done on one day.
+type DailyStats struct {
+	// Date is the day as 2006-01-02.
+	Date    string `json:"date"`
+	Entries int    `json:"entries"`
+	Words   int    `json:"words"`
+}
+
+// MonthlyStats counts the writing done in one month.
+type MonthlyStats struct {
+	// Month is the month as 2006-01.
+	Month   string `json:"month"`
+	Entries int    `json:"entries"`
+	Words   int    `json:"words"`
+}
+
+// Stats summarizes the writing in a journal. Days are calendar days in the location of the
+// time Stats was given.
+type Stats struct {
+	Entries int `json:"entries"`
+	Words   int `json:"words"`
+	// Days is how many days have at least one entry.
+	Days int `json:"days"`
+	// WordsPerDay averages the words written on the days with an entry.
+	WordsPerDay float64 `json:"wordsPerDay"`
+	// CurrentStreak is how many days in a row up to today have an entry. A streak that ended
+	// yesterday is still current, there's time to write today.
+	CurrentStreak int `json:"currentStreak"`
+	LongestStreak int `json:"longestStreak"`
+	// ByHour and ByWeekday count entries by the hour and weekday they were written, indexed by
+	// the hour and by time.Weekday.
+	ByHour    [24]int `json:"byHour"`
+	ByWeekday [7]int  `json:"byWeekday"`
+	// ByMonth and ByDay count the writing in each month and day, oldest first. Those without
+	// any writing are left out.
+	ByMonth []MonthlyStats `json:"byMonth"`
+	ByDay   []DailyStats   `json:"byDay"`
+}
+
+// Stats summarizes the writing in the journal as of now.
+func (j *Journal) Stats(now time.Time) (Stats, error) {
+	loc := now.Location()
+	s := Stats{
+		ByMonth: make([]MonthlyStats, 0),
+		ByDay:   make([]DailyStats, 0),
+	}
+	days := make(map[string]*DailyStats)
+	months := make(map[string]*MonthlyStats)
+
+	err := j.forEachEntryStats(func(_ int, es entryStats) error {
+		t := es.CreateTime.In(loc)
+		s.Entries++
+		s.Words += es.Words
+		s.ByHour[t.Hour()]++
+		s.ByWeekday[t.Weekday()]++
+
+		date := t.Format("2006-01-02")
+		d, ok := days[date]
+		if !ok {
+			d = &DailyStats{Date: date}
+			days[date] = d
+		}
+		d.Entries++
+		d.Words += es.Words
+
+		month := t.Format("2006-01")
+		m, ok := months[month]
+		if !ok {
+			m = &MonthlyStats{Month: month}
+			months[month] = m
+		}
+		m.Entries++
+		m.Words += es.Words
+
+		return nil
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+
+	for _, d := range days {
+		s.ByDay = append(s.ByDay, *d)
+	}
+	sort.Slice(s.ByDay, func(i, j int) bool {
+		return s.ByDay[i].Date < s.ByDay[j].Date
+	})
+	for _, m := range months {
+		s.ByMonth = append(s.ByMonth, *m)
+	}
+	sort.Slice(s.ByMonth, func(i, j int) bool {
+		return s.ByMonth[i].Month < s.ByMonth[j].Month
+	})
+
+	s.Days = len(s.ByDay)
+	if s.Days > 0 {
+		s.WordsPerDay = float64(s.Words) / float64(s.Days)
+	}
+	s.CurrentStreak, s.LongestStreak = streaks(days, now)
+
+	return s, nil
+}
+
+// streaks returns the current and longest run of consecutive days written on.
+func streaks(days map[string]*DailyStats, now time.Time) (current, longest int) {
+	dates := make([]time.Time, 0, len(days))
+	for date := range days {
+		t, err := time.ParseInLocation("2006-01-02", date, now.Location())
+		if err == nil {
+			dates = append(dates, t)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	run := 0
+	for i, d := range dates {
+		if i > 0 && dates[i-1].AddDate(0, 0, 1).Equal(d) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	today := startOfDay(now)
+	if n := len(dates); n > 0 && (dates[n-1].Equal(today) || dates[n-1].AddDate(0, 0, 1).Equal(today)) {
+		current = run
+	}
+
+	return current, longest
+}
+
+// countWords counts the words of an entry's title and content.
+func countWords(e Entry) int {
+	return len(strings.Fields(e.Title)) + len(strings.Fields(e.Content))
+}
+
+// putEntryStats records the stats of e, which is being written in tx.
+func (j *Journal) putEntryStats(tx *bolt.Tx, e Entry) error {
+	data, err := json.Marshal(entryStats{CreateTime: e.CreateTime, Words: countWords(e), Mood: e.Mood})
+	if err != nil {
+		return err
+	}
+	encrypted, err := encrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket([]byte(statsBucketName)).Put(itob(e.ID), encrypted)
+}
+
+// forEachEntryStats calls fn with the ID and stats of every entry, rebuilding the stats first if need be.
+func (j *Journal) forEachEntryStats(fn func(id int, es entryStats) error) error {
+	if err := j.ensureStatsIndex(); err != nil {
+		return err
+	}
+
+	return j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(statsBucketName)).ForEach(func(k, v []byte) error {
+			es, err := j.decodeEntryStats(v)
+			if err != nil {
+				return err
+			}
+			return fn(btoi(k), es)
+		})
+	})
+}
+
+func (j *Journal) decodeEntryStats(data []byte) (entryStats, error) {
+	decrypted, err := decrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return entryStats{}, err
+	}
+
+	var es entryStats
+	err = json.Unmarshal(decrypted, &es)
+	return es, err
+}
+
+// ensureStatsIndex rebuilds the entry stats if they don't cover every entry, as in a journal
+// written before they were kept.
+func (j *Journal) ensureStatsIndex() error {
+	var stale bool
+	err := j.db.View(func(tx *bolt.Tx) error {
+		entries := tx.Bucket([]byte(journalBucketName)).Stats().KeyN
+		stale = entries != tx.Bucket([]byte(statsBucketName)).Stats().KeyN
+		return nil
+	})
+	if err != nil || !stale {
+		return err
+	}
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		if derr := tx.DeleteBucket([]byte(statsBucketName)); derr != nil {
+			return derr
+		}
+		if _, cerr := tx.CreateBucket([]byte(statsBucketName)); cerr != nil {
+			return cerr
+		}
+
+		return tx.Bucket([]byte(journalBucketName)).ForEach(func(k, v []byte) error {
+			e, err := j.decodeEntry(v)
+			if err != nil {
+				return err
+			}
+			return j.putEntryStats(tx, e)
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package jrnl
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestJournal_Stats(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	// Friday the 6th of January 2023, in the evening.
+	now := time.Date(2023, time.January, 6, 21, 0, 0, 0, time.UTC)
+	day := func(n, hour int) time.Time {
+		return time.Date(2023, time.January, n, hour, 0, 0, 0, time.UTC)
+	}
+	mustPutEntry(t, j, Entry{ID: 1, Content: "one two three", CreateTime: time.Date(2022, time.December, 30, 9, 0, 0, 0, time.UTC)})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "one two", CreateTime: time.Date(2022, time.December, 31, 9, 0, 0, 0, time.UTC)})
+	mustPutEntry(t, j, Entry{ID: 3, Title: "New year", Content: "one", CreateTime: day(1, 9)})
+	mustPutEntry(t, j, Entry{ID: 4, Content: "four", CreateTime: day(4, 22)})
+	mustPutEntry(t, j, Entry{ID: 5, Content: "five words in this one", CreateTime: day(5, 22)})
+	mustPutEntry(t, j, Entry{ID: 6, Content: "to delete", CreateTime: day(5, 23)})
+	if err := j.DeleteEntry(6); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := j.Stats(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Entries != 5 || s.Words != 14 || s.Days != 5 || s.WordsPerDay != 14.0/5 {
+		t.Errorf("Stats() totals = %d entries, %d words, %d days, %v words a day", s.Entries, s.Words, s.Days, s.WordsPerDay)
+	}
+	if s.CurrentStreak != 2 || s.LongestStreak != 3 {
+		t.Errorf("Stats() streaks = %d current, %d longest, want 2 and 3", s.CurrentStreak, s.LongestStreak)
+	}
+	if s.ByHour[9] != 3 || s.ByHour[22] != 2 || s.ByWeekday[time.Thursday] != 1 || s.ByWeekday[time.Friday] != 1 {
+		t.Errorf("Stats() ByHour = %v, ByWeekday = %v", s.ByHour, s.ByWeekday)
+	}
+	wantMonths := []MonthlyStats{{Month: "2022-12", Entries: 2, Words: 5}, {Month: "2023-01", Entries: 3, Words: 9}}
+	if diff := cmp.Diff(s.ByMonth, wantMonths); diff != "" {
+		t.Errorf("Stats() ByMonth (-got, +want):\n%s", diff)
+	}
+	if len(s.ByDay) != 5 || s.ByDay[4] != (DailyStats{Date: "2023-01-05", Entries: 1, Words: 5}) {
+		t.Errorf("Stats() ByDay = %v", s.ByDay)
+	}
+
+	if s, err = j.Stats(now.AddDate(0, 0, 2)); err != nil || s.CurrentStreak != 0 {
+		t.Errorf("Stats() two days later has a current streak of %d, %v", s.CurrentStreak, err)
+	}
+
+	if _, err = j.EditEntry(5, "shorter now"); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = j.Stats(now); err != nil || s.Words != 11 {
+		t.Errorf("Stats() after an edit has %d words, %v", s.Words, err)
+	}
+
+	err = j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(statsBucketName)).ForEach(func(_, v []byte) error {
+			if bytes.Contains(v, []byte("Words")) {
+				t.Errorf("entry stats stored in plaintext")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJournal_Stats_Rebuild(t *testing.T) {
+	j := mustNewTestJournal(t)
+	mustCreateEntry(t, j, "written before stats were kept")
+	mustCreateEntry(t, j, "and this one")
+
+	// drop the stats as if the journal were written by an older version.
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(statsBucketName)).Delete(itob(1))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := j.Stats(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Entries != 2 || s.Words != 8 {
+		t.Errorf("Stats() after a rebuild = %d entries, %d words, want 2 and 8", s.Entries, s.Words)
+	}
+}
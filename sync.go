@@ -0,0 +1,662 @@
+package jrnl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	syncDeviceKey   = "device"
+	syncLastKey     = "last"
+	syncRecordPrfx  = "e/"
+	manifestKey     = "manifest"
+	remoteEntryPrfx = "entries/"
+	manifestVersion = 1
+	// manifestAttempts is how many times Push writes the manifest before giving up on it.
+	manifestAttempts = 3
+)
+
+var (
+	// ErrObjectNotFound is returned by an ObjectStore when a key does not exist.
+	ErrObjectNotFound = errors.New("object not found")
+
+	// ErrSyncNotConfigured is returned when syncing a journal that has no remote.
+	ErrSyncNotConfigured = errors.New("sync is not configured")
+
+	// ErrRemoteAhead is returned by Push when the remote has changes that haven't been pulled.
+	ErrRemoteAhead = errors.New("remote has changes that must be pulled first")
+
+	// ErrManifestContended is returned by Push when other devices kept overwriting the remote
+	// manifest while it was being written.
+	ErrManifestContended = errors.New("remote manifest is being written by another device")
+)
+
+// ObjectStore is a minimal blob store that a journal can be synced to.
+// Everything written to an ObjectStore is encrypted before it leaves the journal.
+type ObjectStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// VersionVector tracks how many times each device has changed an entry.
+type VersionVector map[string]uint64
+
+// Descends reports whether v has seen every change recorded in other.
+func (v VersionVector) Descends(other VersionVector) bool {
+	for device, n := range other {
+		if v[device] < n {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge returns a vector holding the highest counter for each device in v and other.
+func (v VersionVector) Merge(other VersionVector) VersionVector {
+	merged := make(VersionVector, len(v)+len(other))
+	for device, n := range v {
+		merged[device] = n
+	}
+	for device, n := range other {
+		if n > merged[device] {
+			merged[device] = n
+		}
+	}
+	return merged
+}
+
+func (v VersionVector) bump(device string) VersionVector {
+	bumped := v.Merge(nil)
+	bumped[device]++
+	return bumped
+}
+
+// SyncStatus summarizes the difference between the local journal and its remote.
+type SyncStatus struct {
+	ToPush    int
+	ToPull    int
+	Conflicts int
+	LastSync  time.Time
+}
+
+// SyncResult describes what a push or pull changed.
+type SyncResult struct {
+	Pushed    int
+	Pulled    int
+	Deleted   int
+	Conflicts []Conflict
+}
+
+// Conflict records an entry that was edited on two devices since they last synced.
+// The local entry is kept as is and the remote version is saved as a new entry.
+type Conflict struct {
+	EntryID int
+	CopyID  int
+}
+
+type syncManifest struct {
+	Version int                      `json:"version"`
+	Entries map[string]manifestEntry `json:"entries"`
+}
+
+type manifestEntry struct {
+	Version VersionVector `json:"version"`
+	Deleted bool          `json:"deleted,omitempty"`
+}
+
+// syncRecord is the local bookkeeping for an entry as of its last sync.
+type syncRecord struct {
+	EntryID  int           `json:"entryId"`
+	RemoteID string        `json:"remoteId"`
+	Version  VersionVector `json:"version"`
+	Hash     string        `json:"hash"`
+	Deleted  bool          `json:"deleted,omitempty"`
+}
+
+// syncItem pairs an entry's local and remote state.
+type syncItem struct {
+	remoteID string
+	record   *syncRecord
+	entry    *Entry
+	remote   *manifestEntry
+}
+
+func (it syncItem) localChanged() bool {
+	if it.record == nil {
+		return it.entry != nil
+	}
+	// a push whose manifest another device overwrote leaves the record ahead of the remote,
+	// so the change is pushed again.
+	if it.remote == nil || !it.remote.Version.Descends(it.record.Version) {
+		return true
+	}
+	if it.entry == nil {
+		return !it.record.Deleted
+	}
+	return it.record.Deleted || hashEntry(*it.entry) != it.record.Hash
+}
+
+func (it syncItem) remoteChanged() bool {
+	if it.remote == nil {
+		return false
+	}
+	if it.record == nil {
+		return !it.remote.Deleted
+	}
+	return !it.record.Version.Descends(it.remote.Version)
+}
+
+type syncPlan struct {
+	device   string
+	manifest syncManifest
+	items    []syncItem
+}
+
+// EnableSync attaches a remote object store that the journal can be pushed to and pulled from.
+func (j *Journal) EnableSync(store ObjectStore) {
+	j.remote = store
+}
+
+// SyncEnabled tells us if the journal has a remote to sync with.
+func (j *Journal) SyncEnabled() bool {
+	return j.remote != nil
+}
+
+// LastSyncStatus returns the status recorded by the most recent sync operation
+// without contacting the remote.
+func (j *Journal) LastSyncStatus() SyncStatus {
+	j.syncMu.Lock()
+	defer j.syncMu.Unlock()
+	return j.syncStatus
+}
+
+// SyncStatus compares the local journal with the remote.
+func (j *Journal) SyncStatus(ctx context.Context) (SyncStatus, error) {
+	plan, err := j.planSync(ctx)
+	if err != nil {
+		return SyncStatus{}, err
+	}
+
+	status := SyncStatus{}
+	for _, it := range plan.items {
+		local, remote := it.localChanged(), it.remoteChanged()
+		switch {
+		case local && remote:
+			status.Conflicts++
+		case local:
+			status.ToPush++
+		case remote:
+			status.ToPull++
+		}
+	}
+
+	status.LastSync, err = j.lastSync()
+	if err != nil {
+		return SyncStatus{}, err
+	}
+
+	j.syncMu.Lock()
+	j.syncStatus = status
+	j.syncMu.Unlock()
+
+	return status, nil
+}
+
+// Sync pulls remote changes into the journal and then pushes local changes to the remote.
+func (j *Journal) Sync(ctx context.Context) (SyncResult, error) {
+	pulled, err := j.Pull(ctx)
+	if err != nil {
+		return pulled, err
+	}
+
+	pushed, err := j.Push(ctx)
+	pushed.Pulled = pulled.Pulled
+	pushed.Deleted += pulled.Deleted
+	pushed.Conflicts = pulled.Conflicts
+
+	return pushed, err
+}
+
+// Pull applies changes made on other devices to the local journal.
+// When an entry was changed both locally and remotely the local entry is kept
+// and the remote version is stored as a new entry, which is reported as a Conflict.
+func (j *Journal) Pull(ctx context.Context) (SyncResult, error) {
+	plan, err := j.planSync(ctx)
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	// download everything before touching the database so we never hold a
+	// write transaction open across network calls.
+	remoteEntries := make(map[string]Entry)
+	for _, it := range plan.items {
+		if !it.remoteChanged() || it.remote.Deleted {
+			continue
+		}
+		e, err := j.fetchEntry(ctx, it.remoteID)
+		if err != nil {
+			return SyncResult{}, err
+		}
+		remoteEntries[it.remoteID] = e
+	}
+
+	var result SyncResult
+	err = j.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket([]byte(journalBucketName))
+		records := tx.Bucket([]byte(syncBucketName))
+
+		for _, it := range plan.items {
+			if !it.remoteChanged() {
+				continue
+			}
+
+			remote, fetched := remoteEntries[it.remoteID]
+			rec := syncRecord{RemoteID: it.remoteID}
+			if it.record != nil {
+				rec = *it.record
+			}
+
+			if it.localChanged() {
+				// both sides changed, keep the local entry and bring the remote one in as a copy.
+				rec.Version = rec.Version.Merge(it.remote.Version)
+				if err := j.putSyncRecord(records, rec); err != nil {
+					return err
+				}
+				if !fetched {
+					continue
+				}
+
+				id, err := entries.NextSequence()
+				if err != nil {
+					return err
+				}
+				remote.ID = int(id)
+				remote.Content = conflictNotice(remote.UpdateTime) + remote.Content
+				if err = j.putEntry(entries, remote); err != nil {
+					return err
+				}
+				result.Conflicts = append(result.Conflicts, Conflict{EntryID: rec.EntryID, CopyID: remote.ID})
+				continue
+			}
+
+			rec.Version = it.remote.Version
+			if !fetched {
+				if it.entry != nil {
+					if err := j.deleteEntry(entries, it.entry.ID); err != nil {
+						return err
+					}
+					result.Deleted++
+				}
+				rec.Deleted = true
+				rec.Hash = ""
+				if err := j.putSyncRecord(records, rec); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if it.entry != nil {
+				remote.ID = it.entry.ID
+			} else {
+				id, err := entries.NextSequence()
+				if err != nil {
+					return err
+				}
+				remote.ID = int(id)
+			}
+			if err := j.putEntry(entries, remote); err != nil {
+				return err
+			}
+
+			rec.EntryID = remote.ID
+			rec.Hash = hashEntry(remote)
+			rec.Deleted = false
+			if err := j.putSyncRecord(records, rec); err != nil {
+				return err
+			}
+			result.Pulled++
+		}
+
+		return records.Put([]byte(syncLastKey), []byte(time.Now().UTC().Format(time.RFC3339)))
+	})
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	_, err = j.SyncStatus(ctx)
+
+	return result, err
+}
+
+// Push uploads local changes to the remote. It fails with ErrRemoteAhead
+// if the remote has changes that haven't been pulled yet.
+//
+// Object stores don't offer conditional writes, so the manifest is merged with the one on the
+// remote just before it's written and read back after, see publishManifest. A device whose
+// changes are still lost to a push landing in between finds them missing from the manifest
+// on its next sync and pushes them again.
+func (j *Journal) Push(ctx context.Context) (SyncResult, error) {
+	plan, err := j.planSync(ctx)
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	for _, it := range plan.items {
+		if it.remoteChanged() {
+			return SyncResult{}, ErrRemoteAhead
+		}
+	}
+
+	var (
+		result  SyncResult
+		updated []syncRecord
+		changes = make(map[string]manifestEntry)
+	)
+	for _, it := range plan.items {
+		if !it.localChanged() {
+			continue
+		}
+
+		rec := syncRecord{RemoteID: it.remoteID}
+		if it.record != nil {
+			rec = *it.record
+		}
+		if rec.RemoteID == "" {
+			if rec.RemoteID, err = randomID(); err != nil {
+				return result, err
+			}
+		}
+		rec.Version = rec.Version.bump(plan.device)
+
+		if it.entry == nil {
+			if err = j.remote.Delete(ctx, remoteEntryPrfx+rec.RemoteID); err != nil && !errors.Is(err, ErrObjectNotFound) {
+				return result, err
+			}
+			rec.Deleted = true
+			rec.Hash = ""
+			result.Deleted++
+		} else {
+			data, err := j.encodeEntry(*it.entry)
+			if err != nil {
+				return result, err
+			}
+			if err = j.remote.Put(ctx, remoteEntryPrfx+rec.RemoteID, data); err != nil {
+				return result, err
+			}
+			rec.EntryID = it.entry.ID
+			rec.Hash = hashEntry(*it.entry)
+			rec.Deleted = false
+			result.Pushed++
+		}
+
+		changes[rec.RemoteID] = manifestEntry{Version: rec.Version, Deleted: rec.Deleted}
+		updated = append(updated, rec)
+	}
+
+	if len(updated) > 0 {
+		if err = j.publishManifest(ctx, changes); err != nil {
+			return result, err
+		}
+	}
+
+	err = j.db.Update(func(tx *bolt.Tx) error {
+		records := tx.Bucket([]byte(syncBucketName))
+		for _, rec := range updated {
+			if err := j.putSyncRecord(records, rec); err != nil {
+				return err
+			}
+		}
+
+		return records.Put([]byte(syncLastKey), []byte(time.Now().UTC().Format(time.RFC3339)))
+	})
+	if err != nil {
+		return result, err
+	}
+
+	_, err = j.SyncStatus(ctx)
+
+	return result, err
+}
+
+// planSync loads the remote manifest and the local sync records and pairs them up per entry.
+func (j *Journal) planSync(ctx context.Context) (syncPlan, error) {
+	if j.remote == nil {
+		return syncPlan{}, ErrSyncNotConfigured
+	}
+
+	manifest, err := j.getManifest(ctx)
+	if err != nil {
+		return syncPlan{}, err
+	}
+
+	plan := syncPlan{manifest: manifest}
+	err = j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(syncBucketName))
+
+		device := b.Get([]byte(syncDeviceKey))
+		if device == nil {
+			id, err := randomID()
+			if err != nil {
+				return err
+			}
+			device = []byte(id)
+			if err = b.Put([]byte(syncDeviceKey), device); err != nil {
+				return err
+			}
+		}
+		plan.device = string(device)
+
+		entries := make(map[int]Entry)
+		err := tx.Bucket([]byte(journalBucketName)).ForEach(func(k, v []byte) error {
+			e, err := j.decodeEntry(v)
+			if err != nil {
+				return err
+			}
+			entries[e.ID] = e
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool)
+		c := b.Cursor()
+		prefix := []byte(syncRecordPrfx)
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			decrypted, err := decrypt([]byte(j.hashedPassword), v)
+			if err != nil {
+				return err
+			}
+			var rec syncRecord
+			if err = json.Unmarshal(decrypted, &rec); err != nil {
+				return err
+			}
+
+			it := syncItem{remoteID: rec.RemoteID, record: &rec}
+			if e, ok := entries[rec.EntryID]; ok && rec.EntryID != 0 {
+				it.entry = &e
+				delete(entries, rec.EntryID)
+			}
+			if m, ok := manifest.Entries[rec.RemoteID]; ok {
+				it.remote = &m
+			}
+			seen[rec.RemoteID] = true
+			plan.items = append(plan.items, it)
+		}
+
+		for remoteID, m := range manifest.Entries {
+			if seen[remoteID] {
+				continue
+			}
+			m := m
+			plan.items = append(plan.items, syncItem{remoteID: remoteID, remote: &m})
+		}
+
+		for _, e := range entries {
+			e := e
+			plan.items = append(plan.items, syncItem{entry: &e})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return syncPlan{}, err
+	}
+
+	return plan, nil
+}
+
+func (j *Journal) getManifest(ctx context.Context) (syncManifest, error) {
+	manifest := syncManifest{Version: manifestVersion, Entries: make(map[string]manifestEntry)}
+
+	data, err := j.remote.Get(ctx, manifestKey)
+	if errors.Is(err, ErrObjectNotFound) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+
+	decrypted, err := decrypt([]byte(j.hashedPassword), data)
+	if err != nil {
+		return manifest, fmt.Errorf("decrypting sync manifest: %w", err)
+	}
+	if err = json.Unmarshal(decrypted, &manifest); err != nil {
+		return manifest, err
+	}
+	if manifest.Version > manifestVersion {
+		return manifest, fmt.Errorf("sync manifest version %d is newer than supported version %d", manifest.Version, manifestVersion)
+	}
+	if manifest.Entries == nil {
+		manifest.Entries = make(map[string]manifestEntry)
+	}
+
+	return manifest, nil
+}
+
+// publishManifest writes changes into the remote manifest. Another device may have pushed since
+// the manifest was planned against, so it's read again and changes merged into it, failing with
+// ErrRemoteAhead if the other device changed one of the same entries. It's then read back to
+// check a push landing in between didn't overwrite it, and written again if one did.
+func (j *Journal) publishManifest(ctx context.Context, changes map[string]manifestEntry) error {
+	for attempt := 0; attempt < manifestAttempts; attempt++ {
+		manifest, err := j.getManifest(ctx)
+		if err != nil {
+			return err
+		}
+		for remoteID, m := range changes {
+			if current, ok := manifest.Entries[remoteID]; ok && !m.Version.Descends(current.Version) {
+				return ErrRemoteAhead
+			}
+			manifest.Entries[remoteID] = m
+		}
+		if err = j.putManifest(ctx, manifest); err != nil {
+			return err
+		}
+
+		written, err := j.getManifest(ctx)
+		if err != nil {
+			return err
+		}
+		if manifestHas(written, changes) {
+			return nil
+		}
+	}
+
+	return ErrManifestContended
+}
+
+// manifestHas reports whether every change in changes is recorded in manifest.
+func manifestHas(manifest syncManifest, changes map[string]manifestEntry) bool {
+	for remoteID, m := range changes {
+		current, ok := manifest.Entries[remoteID]
+		if !ok || current.Deleted != m.Deleted || !current.Version.Descends(m.Version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (j *Journal) putManifest(ctx context.Context, manifest syncManifest) error {
+	manifest.Version = manifestVersion
+	buf, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := encrypt([]byte(j.hashedPassword), buf)
+	if err != nil {
+		return err
+	}
+
+	return j.remote.Put(ctx, manifestKey, encrypted)
+}
+
+func (j *Journal) fetchEntry(ctx context.Context, remoteID string) (Entry, error) {
+	data, err := j.remote.Get(ctx, remoteEntryPrfx+remoteID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("fetching entry %s: %w", remoteID, err)
+	}
+
+	return j.decodeEntry(data)
+}
+
+func (j *Journal) putSyncRecord(b *bolt.Bucket, rec syncRecord) error {
+	buf, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := encrypt([]byte(j.hashedPassword), buf)
+	if err != nil {
+		return err
+	}
+
+	return b.Put([]byte(syncRecordPrfx+rec.RemoteID), encrypted)
+}
+
+func (j *Journal) lastSync() (time.Time, error) {
+	var last time.Time
+	err := j.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(syncBucketName)).Get([]byte(syncLastKey))
+		if data == nil {
+			return nil
+		}
+
+		var err error
+		last, err = time.Parse(time.RFC3339, string(data))
+		return err
+	})
+
+	return last, err
+}
+
+// hashEntry fingerprints the parts of an entry that are synced, ignoring its local ID.
+func hashEntry(e Entry) string {
+	e.ID = 0
+	buf, _ := json.Marshal(e)
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:])
+}
+
+func conflictNotice(t time.Time) string {
+	return fmt.Sprintf("> **Sync conflict:** this copy was edited on another device (%s).\n\n", t.Format(time.RFC1123))
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,327 @@
+package jrnl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestJournal_Sync(t *testing.T) {
+	t.Run("entries pushed from one device are pulled by another", func(t *testing.T) {
+		store := newMemStore()
+		laptop := mustNewSyncedJournal(t, store)
+		desktop := mustNewSyncedJournal(t, store)
+
+		mustCreateEntry(t, laptop, "written on the laptop")
+		mustPush(t, laptop)
+
+		res, err := desktop.Pull(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Pulled != 1 {
+			t.Errorf("Pull() pulled = %d, want 1", res.Pulled)
+		}
+
+		entries, err := desktop.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Content != "written on the laptop" {
+			t.Errorf("ListEntries() = %+v, want the laptop's entry", entries)
+		}
+	})
+
+	t.Run("only ciphertext is stored remotely", func(t *testing.T) {
+		store := newMemStore()
+		j := mustNewSyncedJournal(t, store)
+
+		mustCreateEntry(t, j, "a very secret thought")
+		mustPush(t, j)
+
+		for key, data := range store.objects {
+			if bytes.Contains(data, []byte("secret")) {
+				t.Errorf("object %q contains plaintext", key)
+			}
+		}
+	})
+
+	t.Run("edits and deletes propagate", func(t *testing.T) {
+		store := newMemStore()
+		laptop := mustNewSyncedJournal(t, store)
+		desktop := mustNewSyncedJournal(t, store)
+
+		kept := mustCreateEntry(t, laptop, "keep me")
+		removed := mustCreateEntry(t, laptop, "delete me")
+		mustPush(t, laptop)
+		mustPull(t, desktop)
+
+		if _, err := laptop.EditEntry(kept.ID, "keep me, edited"); err != nil {
+			t.Fatal(err)
+		}
+		if err := laptop.DeleteEntry(removed.ID); err != nil {
+			t.Fatal(err)
+		}
+		mustPush(t, laptop)
+
+		res := mustPull(t, desktop)
+		if res.Pulled != 1 || res.Deleted != 1 {
+			t.Errorf("Pull() = %+v, want 1 pulled and 1 deleted", res)
+		}
+
+		entries, err := desktop.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Content != "keep me, edited" {
+			t.Errorf("ListEntries() = %+v, want only the edited entry", entries)
+		}
+	})
+
+	t.Run("push is rejected when the remote is ahead", func(t *testing.T) {
+		store := newMemStore()
+		laptop := mustNewSyncedJournal(t, store)
+		desktop := mustNewSyncedJournal(t, store)
+
+		mustCreateEntry(t, laptop, "first")
+		mustPush(t, laptop)
+
+		_, err := desktop.Push(context.Background())
+		if !errors.Is(err, ErrRemoteAhead) {
+			t.Errorf("Push() error = %v, want %v", err, ErrRemoteAhead)
+		}
+	})
+
+	t.Run("concurrent edits keep both versions", func(t *testing.T) {
+		store := newMemStore()
+		laptop := mustNewSyncedJournal(t, store)
+		desktop := mustNewSyncedJournal(t, store)
+
+		e := mustCreateEntry(t, laptop, "original")
+		mustPush(t, laptop)
+		mustPull(t, desktop)
+
+		desktopEntries, err := desktop.ListEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = laptop.EditEntry(e.ID, "laptop edit"); err != nil {
+			t.Fatal(err)
+		}
+		mustPush(t, laptop)
+
+		if _, err = desktop.EditEntry(desktopEntries[0].ID, "desktop edit"); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err := desktop.SyncStatus(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.Conflicts != 1 {
+			t.Errorf("SyncStatus() conflicts = %d, want 1", status.Conflicts)
+		}
+
+		res, err := desktop.Sync(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Conflicts) != 1 {
+			t.Fatalf("Sync() conflicts = %+v, want 1", res.Conflicts)
+		}
+
+		mustPull(t, laptop)
+		for _, j := range []*Journal{laptop, desktop} {
+			entries, err := j.ListEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 2 {
+				t.Errorf("ListEntries() returned %d entries, want 2", len(entries))
+			}
+
+			var sawDesktop, sawLaptop bool
+			for _, entry := range entries {
+				sawDesktop = sawDesktop || entry.Content == "desktop edit"
+				sawLaptop = sawLaptop || bytes.HasSuffix([]byte(entry.Content), []byte("laptop edit"))
+			}
+			if !sawDesktop || !sawLaptop {
+				t.Errorf("ListEntries() = %+v, want both edits", entries)
+			}
+		}
+
+		status, err = laptop.SyncStatus(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.ToPush != 0 || status.ToPull != 0 || status.Conflicts != 0 {
+			t.Errorf("SyncStatus() = %+v, want everything in sync", status)
+		}
+	})
+
+	t.Run("a manifest overwritten during a push is written again", func(t *testing.T) {
+		store := newMemStore()
+		laptop := mustNewSyncedJournal(t, store)
+		desktop := mustNewSyncedJournal(t, store)
+
+		// another device that read the empty manifest writes it back just after the laptop's push.
+		overwritten := false
+		store.afterPut = func(key string) {
+			if key == manifestKey && !overwritten {
+				overwritten = true
+				_ = store.Delete(context.Background(), manifestKey)
+			}
+		}
+
+		mustCreateEntry(t, laptop, "written on the laptop")
+		mustPush(t, laptop)
+		if !overwritten {
+			t.Fatal("the manifest wasn't overwritten")
+		}
+
+		if res := mustPull(t, desktop); res.Pulled != 1 {
+			t.Errorf("Pull() pulled = %d, want 1", res.Pulled)
+		}
+	})
+
+	t.Run("changes lost to another device's push are pushed again", func(t *testing.T) {
+		store := newMemStore()
+		laptop := mustNewSyncedJournal(t, store)
+		desktop := mustNewSyncedJournal(t, store)
+
+		mustCreateEntry(t, laptop, "written on the laptop")
+		mustPush(t, laptop)
+		// a push that read the manifest before the laptop's and wrote it after the laptop checked it.
+		if err := store.Delete(context.Background(), manifestKey); err != nil {
+			t.Fatal(err)
+		}
+
+		status, err := laptop.SyncStatus(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.ToPush != 1 {
+			t.Errorf("SyncStatus() to push = %d, want 1", status.ToPush)
+		}
+
+		mustPush(t, laptop)
+		if res := mustPull(t, desktop); res.Pulled != 1 {
+			t.Errorf("Pull() pulled = %d, want 1", res.Pulled)
+		}
+	})
+
+	t.Run("sync without a remote", func(t *testing.T) {
+		f, closeFunc := mustNewTestFile(t)
+		t.Cleanup(closeFunc)
+
+		j, jCloseFunc := mustNewAuthenticatedJournal(t, f.Name())
+		t.Cleanup(jCloseFunc)
+
+		if _, err := j.Sync(context.Background()); !errors.Is(err, ErrSyncNotConfigured) {
+			t.Errorf("Sync() error = %v, want %v", err, ErrSyncNotConfigured)
+		}
+	})
+}
+
+func TestVersionVector_Descends(t *testing.T) {
+	tests := []struct {
+		name  string
+		v     VersionVector
+		other VersionVector
+		want  bool
+	}{
+		{name: "equal", v: VersionVector{"a": 1}, other: VersionVector{"a": 1}, want: true},
+		{name: "newer", v: VersionVector{"a": 2, "b": 1}, other: VersionVector{"a": 1}, want: true},
+		{name: "older", v: VersionVector{"a": 1}, other: VersionVector{"a": 2}, want: false},
+		{name: "concurrent", v: VersionVector{"a": 2}, other: VersionVector{"b": 1}, want: false},
+		{name: "empty", v: nil, other: nil, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Descends(tt.other); got != tt.want {
+				t.Errorf("Descends() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustNewSyncedJournal(tb testing.TB, store ObjectStore) *Journal {
+	tb.Helper()
+
+	f, closeFunc := mustNewTestFile(tb)
+	tb.Cleanup(closeFunc)
+
+	j, jCloseFunc := mustNewAuthenticatedJournal(tb, f.Name())
+	tb.Cleanup(jCloseFunc)
+
+	j.EnableSync(store)
+
+	return j
+}
+
+func mustPush(tb testing.TB, j *Journal) SyncResult {
+	tb.Helper()
+
+	res, err := j.Push(context.Background())
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return res
+}
+
+func mustPull(tb testing.TB, j *Journal) SyncResult {
+	tb.Helper()
+
+	res, err := j.Pull(context.Background())
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return res
+}
+
+// memStore is an in-memory ObjectStore.
+type memStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	// afterPut, if set, is called after each Put, standing in for other devices writing to the store.
+	afterPut func(key string)
+}
+
+func newMemStore() *memStore {
+	return &memStore{objects: make(map[string][]byte)}
+}
+
+func (s *memStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (s *memStore) Put(_ context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	s.objects[key] = append([]byte(nil), data...)
+	s.mu.Unlock()
+
+	if s.afterPut != nil {
+		s.afterPut(key)
+	}
+	return nil
+}
+
+func (s *memStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects, key)
+	return nil
+}
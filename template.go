@@ -0,0 +1,217 @@
+package jrnl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// templateExt is the extension of template files in a template folder.
+const templateExt = ".md"
+
+var (
+	// ErrTemplateNotFound is returned when there is no template with the given name.
+	ErrTemplateNotFound = errors.New("template not found")
+
+	// templateVar matches a variable in a template, e.g. {{date}} or {{ weekday }}.
+	templateVar = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+	// todoItem matches an unfinished markdown task list item, "- [ ] buy milk".
+	todoItem = regexp.MustCompile(`^\s*[-*+] \[ \] \S`)
+
+	// templateNameChars are the characters a template name may use, so it can also be a file name.
+	templateNameChars = regexp.MustCompile(`^[\w-]+$`)
+)
+
+// templatePrompts are the writing prompts {{prompt}} picks from, a different one each day.
+var templatePrompts = []string{
+	"What made you smile today?",
+	"What is taking up most of your attention right now?",
+	"What did you learn today?",
+	"What would you do differently if you could do today again?",
+	"Who are you grateful for, and why?",
+	"What are you looking forward to?",
+	"What drained your energy today, and what restored it?",
+	"What is one small thing you can do tomorrow to make it better?",
+	"What did you avoid today?",
+	"Describe a moment from today in as much detail as you can.",
+	"What would you tell yourself a year ago?",
+	"What is a question you keep coming back to?",
+}
+
+// Template is the starting content for a new entry. Its content may use the variables
+//
+//	{{date}}     today's date, 2006-01-02
+//	{{time}}     the time, 15:04
+//	{{weekday}}  the day of the week, Monday
+//	{{prompt}}   a writing prompt, a different one each day
+//	{{todos}}    the unfinished "- [ ]" tasks of the previous entry
+//
+// Any other {{...}} is left as it is.
+type Template struct {
+	Name    string
+	Content string
+}
+
+// DefaultTemplates are available in every journal unless a template with the same name replaces them.
+var DefaultTemplates = []Template{
+	{Name: "daily-review", Content: "# {{weekday}} {{date}}\n\n## What went well\n\n\n## What didn't\n\n\n## Carried over\n\n{{todos}}\n"},
+	{Name: "dream-log", Content: "# Dream, {{date}}\n\n## What happened\n\n\n## How it felt\n\n\n## Anything familiar\n\n"},
+	{Name: "gratitude", Content: "# Gratitude, {{weekday}} {{date}}\n\n1. \n2. \n3. \n\n{{prompt}}\n\n"},
+	{Name: "standup", Content: "# Standup {{date}}\n\n## Yesterday\n\n{{todos}}\n\n## Today\n\n- [ ] \n\n## Blockers\n\n"},
+}
+
+// SaveTemplate stores t encrypted in the journal, replacing any template with the same name.
+// Names may use letters, digits, '-' and '_'.
+func (j *Journal) SaveTemplate(t Template) error {
+	if !templateNameChars.MatchString(t.Name) {
+		return fmt.Errorf("invalid template name %q: use letters, digits, - and _", t.Name)
+	}
+
+	encrypted, err := encrypt([]byte(j.hashedPassword), []byte(t.Content))
+	if err != nil {
+		return err
+	}
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(templateBucketName)).Put([]byte(t.Name), encrypted)
+	})
+}
+
+// DeleteTemplate removes the template stored in the journal under name.
+func (j *Journal) DeleteTemplate(name string) error {
+	return j.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(templateBucketName))
+		if b.Get([]byte(name)) == nil {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+		return b.Delete([]byte(name))
+	})
+}
+
+// ListTemplates lists the templates available to new entries sorted by name: those stored in
+// the journal, the .md files in dir, which may be empty or missing, and DefaultTemplates. A
+// template in the journal replaces a file of the same name, and both replace a default.
+func (j *Journal) ListTemplates(dir string) ([]Template, error) {
+	byName := make(map[string]Template)
+	for _, t := range DefaultTemplates {
+		byName[t.Name] = t
+	}
+
+	if dir != "" {
+		files, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		for _, f := range files {
+			name := strings.TrimSuffix(f.Name(), templateExt)
+			if f.IsDir() || filepath.Ext(f.Name()) != templateExt || !templateNameChars.MatchString(name) {
+				continue
+			}
+			data, rerr := os.ReadFile(filepath.Join(dir, f.Name()))
+			if rerr != nil {
+				return nil, rerr
+			}
+			byName[name] = Template{Name: name, Content: string(data)}
+		}
+	}
+
+	err := j.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(templateBucketName)).ForEach(func(k, v []byte) error {
+			content, err := decrypt([]byte(j.hashedPassword), v)
+			if err != nil {
+				return err
+			}
+			byName[string(k)] = Template{Name: string(k), Content: string(content)}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]Template, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// FindTemplate returns the template called name from those ListTemplates lists.
+func (j *Journal) FindTemplate(dir, name string) (Template, error) {
+	templates, err := j.ListTemplates(dir)
+	if err != nil {
+		return Template{}, err
+	}
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+
+	return Template{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+}
+
+// RenderTemplate fills in the variables of t for a new entry written at now.
+func (j *Journal) RenderTemplate(t Template, now time.Time) (string, error) {
+	vars := map[string]string{
+		"date":    now.Format("2006-01-02"),
+		"time":    now.Format("15:04"),
+		"weekday": now.Weekday().String(),
+		"prompt":  templatePrompts[now.YearDay()%len(templatePrompts)],
+	}
+
+	// the previous entry is only looked for when it's needed as it means decrypting the journal.
+	for _, m := range templateVar.FindAllStringSubmatch(t.Content, -1) {
+		if m[1] != "todos" {
+			continue
+		}
+		todos, err := j.unfinishedTodos(now)
+		if err != nil {
+			return "", err
+		}
+		vars["todos"] = strings.Join(todos, "\n")
+		break
+	}
+
+	return templateVar.ReplaceAllStringFunc(t.Content, func(m string) string {
+		if v, ok := vars[templateVar.FindStringSubmatch(m)[1]]; ok {
+			return v
+		}
+		return m
+	}), nil
+}
+
+// unfinishedTodos returns the unfinished task list items of the last entry written before now.
+func (j *Journal) unfinishedTodos(now time.Time) ([]string, error) {
+	var prev Entry
+	err := j.forEachEntry(func(e Entry) error {
+		if e.CreateTime.Before(now) && e.CreateTime.After(prev.CreateTime) {
+			prev = e
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []string
+	for _, line := range strings.Split(prev.Content, "\n") {
+		if todoItem.MatchString(line) {
+			todos = append(todos, strings.TrimRight(line, " \t\r"))
+		}
+	}
+
+	return todos, nil
+}
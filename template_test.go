@@ -0,0 +1,95 @@
+package jrnl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJournal_ListTemplates(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"standup.md":    "file standup",
+		"commute.md":    "file commute",
+		"notes.txt":     "not a template",
+		"bad name!.md":  "not a template name",
+		"dream-log.md":  "file dream log",
+		"therapy.draft": "not a template",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := j.SaveTemplate(Template{Name: "dream-log", Content: "stored dream log"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.SaveTemplate(Template{Name: "../escape", Content: "x"}); err == nil {
+		t.Errorf("expected an invalid name to be refused")
+	}
+
+	templates, err := j.ListTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	var names []string
+	for _, tmpl := range templates {
+		got[tmpl.Name] = tmpl.Content
+		names = append(names, tmpl.Name)
+	}
+	if diff := cmp.Diff(names, []string{"commute", "daily-review", "dream-log", "gratitude", "standup"}); diff != "" {
+		t.Errorf("ListTemplates() names (-got, +want):\n%s", diff)
+	}
+	if got["dream-log"] != "stored dream log" || got["standup"] != "file standup" {
+		t.Errorf("expected stored templates to replace files and files to replace defaults, got %v", got)
+	}
+
+	if err = j.DeleteTemplate("dream-log"); err != nil {
+		t.Fatal(err)
+	}
+	if tmpl, _ := j.FindTemplate(dir, "dream-log"); tmpl.Content != "file dream log" {
+		t.Errorf("expected the file to show once the stored template is deleted, got %q", tmpl.Content)
+	}
+	if err = j.DeleteTemplate("dream-log"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("DeleteTemplate() of a missing template = %v, want ErrTemplateNotFound", err)
+	}
+	if _, err = j.FindTemplate("", "nope"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("FindTemplate() of a missing template = %v, want ErrTemplateNotFound", err)
+	}
+}
+
+func TestJournal_RenderTemplate(t *testing.T) {
+	j := mustNewTestJournal(t)
+
+	monday := time.Date(2023, time.January, 2, 9, 5, 0, 0, time.UTC)
+	mustPutEntry(t, j, Entry{ID: 1, Content: "- [ ] stale", CreateTime: monday.AddDate(0, 0, -3)})
+	mustPutEntry(t, j, Entry{ID: 2, Content: "# Friday\n\n- [x] done\n- [ ] write tests\n  * [ ] nested  \n- [ ]\nnot - [ ] a task", CreateTime: monday.AddDate(0, 0, -1)})
+	mustPutEntry(t, j, Entry{ID: 3, Content: "- [ ] from the future", CreateTime: monday.Add(time.Hour)})
+
+	got, err := j.RenderTemplate(Template{Content: "{{weekday}} {{ date }} {{time}}\n{{todos}}\n{{unknown}}"}, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Monday 2023-01-02 09:05\n- [ ] write tests\n  * [ ] nested\n{{unknown}}"
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("RenderTemplate() (-got, +want):\n%s", diff)
+	}
+
+	prompt, err := j.RenderTemplate(Template{Content: "{{prompt}}"}, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := j.RenderTemplate(Template{Content: "{{prompt}}"}, monday.Add(time.Hour)); prompt == "" || prompt != again {
+		t.Errorf("expected the same prompt all day, got %q and %q", prompt, again)
+	}
+	if tomorrow, _ := j.RenderTemplate(Template{Content: "{{prompt}}"}, monday.AddDate(0, 0, 1)); tomorrow == prompt {
+		t.Errorf("expected a different prompt tomorrow, got %q again", prompt)
+	}
+}
@@ -0,0 +1,301 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// calendarPreviewEntries is the most entries of the selected day listed below the calendar.
+const calendarPreviewEntries = 5
+
+var (
+	calendarTitleStyle   = lipgloss.NewStyle().Background(lipgloss.Color("62")).Foreground(lipgloss.Color("230")).Padding(0, 1)
+	calendarWrittenStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("62"))
+)
+
+var calendarKeys = struct {
+	PrevDay   key.Binding
+	NextDay   key.Binding
+	PrevWeek  key.Binding
+	NextWeek  key.Binding
+	PrevMonth key.Binding
+	NextMonth key.Binding
+	Today     key.Binding
+}{
+	PrevDay:   key.NewBinding(key.WithKeys("left", "h")),
+	NextDay:   key.NewBinding(key.WithKeys("right", "l")),
+	PrevWeek:  key.NewBinding(key.WithKeys("up", "k")),
+	NextWeek:  key.NewBinding(key.WithKeys("down", "j")),
+	PrevMonth: key.NewBinding(key.WithKeys("[", "pgup")),
+	NextMonth: key.NewBinding(key.WithKeys("]", "pgdown")),
+	Today:     key.NewBinding(key.WithKeys("g")),
+}
+
+// CalendarUI implements tea.Model. It shows a month at a time with the days that have entries
+// highlighted, and opens the entries of the selected day.
+type CalendarUI struct {
+	jr *jrnl.Journal
+	// byDay holds the entries of each day, keyed by the day as 2006-01-02 and oldest first.
+	byDay map[string][]jrnl.Entry
+	// cursor is the start of the selected day.
+	cursor time.Time
+}
+
+// InitCalendarUI initializes the calendar on today.
+func InitCalendarUI(jr *jrnl.Journal) (tea.Model, error) {
+	return initCalendarUIAt(jr, time.Now())
+}
+
+// initCalendarUIAt initializes the calendar on the day of t.
+func initCalendarUIAt(jr *jrnl.Journal, t time.Time) (tea.Model, error) {
+	ui := CalendarUI{jr: jr, cursor: startOfDay(t)}
+	if err := ui.load(); err != nil {
+		return nil, err
+	}
+	return ui, nil
+}
+
+// load groups the journal's entries by day.
+func (ui *CalendarUI) load() error {
+	entries, err := ui.jr.ListEntries()
+	if err != nil {
+		return err
+	}
+
+	ui.byDay = make(map[string][]jrnl.Entry)
+	for _, e := range entries {
+		date := e.CreateTime.In(time.Local).Format("2006-01-02")
+		ui.byDay[date] = append(ui.byDay[date], e)
+	}
+	for _, day := range ui.byDay {
+		sort.Slice(day, func(i, j int) bool {
+			return day[i].CreateTime.Before(day[j].CreateTime)
+		})
+	}
+
+	return nil
+}
+
+// Init ...
+func (ui CalendarUI) Init() tea.Cmd {
+	return nil
+}
+
+// Update ...
+func (ui CalendarUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		WindowSize = msg
+	case updateEntryListMsg:
+		if err := ui.load(); err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
+	case errMsg:
+		log.Printf("ERROR: %s\n", msg.Error())
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, Keymap.Quit):
+			return ui, tea.Quit
+		case key.Matches(msg, calendarKeys.PrevDay):
+			ui.cursor = ui.cursor.AddDate(0, 0, -1)
+		case key.Matches(msg, calendarKeys.NextDay):
+			ui.cursor = ui.cursor.AddDate(0, 0, 1)
+		case key.Matches(msg, calendarKeys.PrevWeek):
+			ui.cursor = ui.cursor.AddDate(0, 0, -7)
+		case key.Matches(msg, calendarKeys.NextWeek):
+			ui.cursor = ui.cursor.AddDate(0, 0, 7)
+		case key.Matches(msg, calendarKeys.PrevMonth):
+			ui.cursor = addMonths(ui.cursor, -1)
+		case key.Matches(msg, calendarKeys.NextMonth):
+			ui.cursor = addMonths(ui.cursor, 1)
+		case key.Matches(msg, calendarKeys.Today):
+			ui.cursor = startOfDay(time.Now())
+		case key.Matches(msg, Keymap.Enter):
+			return ui.open()
+		case key.Matches(msg, Keymap.Create):
+			return ui.create()
+		case key.Matches(msg, Keymap.Stats):
+			m := InitStatsUI(ui.jr)
+			return m, m.Init()
+		case key.Matches(msg, Keymap.OnThisDay):
+			m, err := InitMemoriesUI(ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m, nil
+		case key.Matches(msg, Keymap.Random):
+			return openRandomEntry(ui, ui.jr)
+		case key.Matches(msg, Keymap.ToggleView):
+			calendarView = false
+			m, err := InitJournalUI(ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m, m.Init()
+		}
+	}
+
+	return ui, nil
+}
+
+// open opens the selected day: its entry if it has one, a list of them if it has several, or
+// the editor on a new entry if it has none.
+func (ui CalendarUI) open() (tea.Model, tea.Cmd) {
+	entries := ui.selected()
+	switch len(entries) {
+	case 0:
+		return ui.create()
+	case 1:
+		m, err := InitEntryUI(entryItem{entries[0]}, ui.jr)
+		if err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
+		return m.Update(WindowSize)
+	}
+
+	m, err := initDayUI(ui.jr, ui.cursor)
+	if err != nil {
+		return ui, func() tea.Msg { return errMsg{err} }
+	}
+	return m, m.Init()
+}
+
+// create opens the editor on a new entry written on the selected day, at the time of day it is
+// now, or with daily notes on the day's entry if there is one.
+func (ui CalendarUI) create() (tea.Model, tea.Cmd) {
+	var e entryItem
+	now := time.Now()
+	if !ui.cursor.Equal(startOfDay(now)) {
+		e.CreateTime = time.Date(ui.cursor.Year(), ui.cursor.Month(), ui.cursor.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.Local)
+	}
+
+	if dailyNotes {
+		m, err := initDayEditorUI(e, ui.jr)
+		if err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
+		return m, m.Init()
+	}
+
+	m := InitEditorUI(e, ui.jr, true)
+	return m, m.Init()
+}
+
+// selected returns the entries of the selected day.
+func (ui CalendarUI) selected() []jrnl.Entry {
+	return ui.byDay[ui.cursor.Format("2006-01-02")]
+}
+
+// View returns the text UI to be output to the terminal.
+func (ui CalendarUI) View() string {
+	_, right, _, left := DocStyle.GetMargin()
+	width := WindowSize.Width - left - right
+
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		calendarTitleStyle.Render(ui.cursor.Format("January 2006")),
+		"",
+		ui.monthView(),
+		"",
+		ui.dayView(width),
+	)
+
+	return DocStyle.Render(body) + "\n" + ui.helpView()
+}
+
+// monthView draws the month of the cursor as a grid of weeks starting on Monday.
+func (ui CalendarUI) monthView() string {
+	first := time.Date(ui.cursor.Year(), ui.cursor.Month(), 1, 0, 0, 0, 0, time.Local)
+	today := startOfDay(time.Now())
+
+	var b strings.Builder
+	b.WriteString(HelpStyle("Mo Tu We Th Fr Sa Su") + "\n")
+	b.WriteString(strings.Repeat("   ", (int(first.Weekday())+6)%7))
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		style := lipgloss.NewStyle()
+		if len(ui.byDay[d.Format("2006-01-02")]) > 0 {
+			style = calendarWrittenStyle.Copy()
+		}
+		if d.Equal(today) {
+			style = style.Underline(true)
+		}
+		if d.Equal(ui.cursor) {
+			style = style.Reverse(true)
+		}
+
+		b.WriteString(style.Render(fmt.Sprintf("%2d", d.Day())))
+		if d.Weekday() == time.Sunday {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+
+	return strings.TrimRight(b.String(), " \n")
+}
+
+// dayView lists the entries of the selected day.
+func (ui CalendarUI) dayView(width int) string {
+	entries := ui.selected()
+
+	var b strings.Builder
+	b.WriteString(statsHeadingStyle.Render(ui.cursor.Format("Monday, 02 January 2006")) + "\n")
+	if len(entries) == 0 {
+		b.WriteString(HelpStyle("Nothing written, press enter to write something."))
+		return b.String()
+	}
+
+	for i, e := range entries {
+		if i == calendarPreviewEntries {
+			fmt.Fprintf(&b, "%s\n", HelpStyle(fmt.Sprintf("and %d more", len(entries)-i)))
+			break
+		}
+
+		item := entryItem{e}
+		line := fmt.Sprintf("%7s  %s", e.CreateTime.In(time.Local).Format("3:04PM"), firstLine(item.Description()))
+		if e.Mood != 0 {
+			line += " " + moodIcon(e.Mood)
+		}
+		if w := max(1, width); lipgloss.Width(line) > w {
+			line = string([]rune(line)[:min(len([]rune(line)), w-1)]) + "…"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (ui CalendarUI) helpView() string {
+	return HelpStyle(" ←/→ day • ↑/↓ week • [/] month • g today • enter open • c create • o on this day • r random • v list • q quit\n")
+}
+
+// addMonths moves t by n months, keeping to the last day of the month when it has fewer days.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+// startOfDay returns midnight at the start of t's day, in the local time zone.
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// firstLine returns the first non-empty line of s.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
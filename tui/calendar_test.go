@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		n    int
+		want time.Time
+	}{
+		{t: date(2023, time.March, 14), n: 1, want: date(2023, time.April, 14)},
+		{t: date(2023, time.January, 31), n: 1, want: date(2023, time.February, 28)},
+		{t: date(2024, time.January, 31), n: 1, want: date(2024, time.February, 29)},
+		{t: date(2023, time.March, 31), n: -1, want: date(2023, time.February, 28)},
+		{t: date(2023, time.December, 15), n: 1, want: date(2024, time.January, 15)},
+		{t: date(2023, time.January, 15), n: -13, want: date(2021, time.December, 15)},
+		{t: date(2023, time.May, 31), n: 0, want: date(2023, time.May, 31)},
+	}
+	for _, tt := range tests {
+		if got := addMonths(tt.t, tt.n); !got.Equal(tt.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.t.Format("2006-01-02"), tt.n, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+	time.Local = time.FixedZone("UTC+10", 10*60*60)
+
+	tests := []struct {
+		t    time.Time
+		want time.Time
+	}{
+		{t: time.Date(2023, time.March, 14, 15, 4, 5, 6, time.Local), want: date(2023, time.March, 14)},
+		{t: date(2023, time.March, 14), want: date(2023, time.March, 14)},
+		// 20:00 UTC is the next morning in UTC+10.
+		{t: time.Date(2023, time.March, 14, 20, 0, 0, 0, time.UTC), want: date(2023, time.March, 15)},
+	}
+	for _, tt := range tests {
+		got := startOfDay(tt.t)
+		if !got.Equal(tt.want) || got.Location() != time.Local {
+			t.Errorf("startOfDay(%s) = %s, want %s", tt.t, got, tt.want)
+		}
+	}
+}
+
+// date returns midnight at the start of the day in the local time zone.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
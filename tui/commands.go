@@ -1,6 +1,9 @@
 package tui
 
 import (
+	"context"
+	"time"
+
 	"github.com/actatum/jrnl"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -13,6 +16,29 @@ type createEntryMsg struct {
 type editEntryMsg struct {
 	entry entryItem
 }
+type syncStatusMsg struct {
+	status jrnl.SyncStatus
+}
+type autosaveMsg struct {
+	session int
+}
+type draftSavedMsg struct {
+	session int
+	draft   jrnl.Draft
+}
+type draftsMsg struct {
+	drafts []jrnl.Draft
+}
+type moodReportMsg struct {
+	report jrnl.MoodReport
+}
+type writingStatsMsg struct {
+	stats jrnl.Stats
+}
+type previewTickMsg struct {
+	session int
+	version int
+}
 
 func deleteEntryCmd(id int, jr *jrnl.Journal) tea.Cmd {
 	return func() tea.Msg {
@@ -30,20 +56,137 @@ func editEntryCmd(e entryItem, jr *jrnl.Journal) tea.Cmd {
 		if err != nil {
 			return errMsg{err}
 		}
+		if e.Mood != entry.Mood {
+			if entry, err = jr.SetMood(e.ID, e.Mood); err != nil {
+				return errMsg{err}
+			}
+		}
 
 		return editEntryMsg{
-			entry: entryItem(entry),
+			entry: entryItem{entry},
+		}
+	}
+}
+
+func createEntryCmd(e entryItem, jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := jr.CreateEntryFrom(e.Entry)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return createEntryMsg{entryItem{entry}}
+	}
+}
+
+func setEntryFieldsCmd(id int, schema string, fields map[string]string, jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := jr.SetEntryFields(id, schema, fields)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return editEntryMsg{entryItem{entry}}
+	}
+}
+
+func setMoodCmd(id, mood int, jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		entry, err := jr.SetMood(id, mood)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return editEntryMsg{entryItem{entry}}
+	}
+}
+
+func syncStatusCmd(jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		status, err := jr.SyncStatus(context.Background())
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return syncStatusMsg{status}
+	}
+}
+
+func syncCmd(jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := jr.Sync(context.Background()); err != nil {
+			return errMsg{err}
+		}
+
+		return updateEntryListMsg{}
+	}
+}
+
+// autosaveCmd ticks after autosaveInterval for the editor with the given session to autosave.
+func autosaveCmd(session int) tea.Cmd {
+	return tea.Tick(autosaveInterval, func(time.Time) tea.Msg {
+		return autosaveMsg{session}
+	})
+}
+
+// previewTickCmd asks for the editor's preview to be rendered once the text has stopped
+// changing for previewDelay, version being the change it was asked for after.
+func previewTickCmd(session, version int) tea.Cmd {
+	return tea.Tick(previewDelay, func(time.Time) tea.Msg {
+		return previewTickMsg{session, version}
+	})
+}
+
+func saveDraftCmd(d jrnl.Draft, session int, jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		draft, err := jr.SaveDraft(d)
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return draftSavedMsg{session: session, draft: draft}
+	}
+}
+
+func deleteDraftCmd(id string, jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		if err := jr.DeleteDraft(id); err != nil {
+			return errMsg{err}
 		}
+
+		return nil
+	}
+}
+
+func listDraftsCmd(jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		drafts, err := jr.ListDrafts()
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return draftsMsg{drafts}
+	}
+}
+
+func moodReportCmd(jr *jrnl.Journal) tea.Cmd {
+	return func() tea.Msg {
+		r, err := jr.MoodReport(time.Time{}, time.Time{})
+		if err != nil {
+			return errMsg{err}
+		}
+
+		return moodReportMsg{r}
 	}
 }
 
-func createEntryCmd(content string, jr *jrnl.Journal) tea.Cmd {
+func writingStatsCmd(jr *jrnl.Journal) tea.Cmd {
 	return func() tea.Msg {
-		entry, err := jr.CreateEntry(content)
+		s, err := jr.Stats(time.Now())
 		if err != nil {
 			return errMsg{err}
 		}
 
-		return createEntryMsg{entryItem(entry)}
+		return writingStatsMsg{s}
 	}
 }
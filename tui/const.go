@@ -13,6 +13,22 @@ const journalTimeLayout = "Mon, 02 Jan 2006 3:04PM MST"
 var (
 	// WindowSize store the size of the terminal window
 	WindowSize tea.WindowSizeMsg
+
+	// dailyNotes is set from the config to keep to one entry per day.
+	dailyNotes bool
+
+	// editorPreview is whether the editor shows a preview of the entry beside the text.
+	editorPreview bool
+
+	// splitPane is whether the list is shown beside a preview of the highlighted entry.
+	splitPane bool
+
+	// calendarView is whether the journal is browsed as a calendar rather than a list.
+	calendarView bool
+
+	// listStarted is set once the list has looked for unsaved drafts and the sync status,
+	// which it does the first time it's shown, whatever view the TUI opened on.
+	listStarted bool
 )
 
 /* STYLING */
@@ -30,14 +46,47 @@ var ErrStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#bd534b")).Render
 var AlertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("62")).Render
 
 type keymap struct {
-	Create    key.Binding
-	Enter     key.Binding
-	Edit      key.Binding
-	Delete    key.Binding
-	Back      key.Binding
-	Quit      key.Binding
-	ForceQuit key.Binding
-	Save      key.Binding
+	Create   key.Binding
+	Template key.Binding
+	Today    key.Binding
+	Fields   key.Binding
+	Mood     key.Binding
+	Stats    key.Binding
+	// OnThisDay lists the entries written on today's date in earlier years, Random opens one
+	// written before today at random.
+	OnThisDay key.Binding
+	Random    key.Binding
+	// NewerEntry and OlderEntry move from the entry being read to the one written after or before it,
+	// Read reads the entries of its month one after another.
+	NewerEntry key.Binding
+	OlderEntry key.Binding
+	Read       key.Binding
+	// Search searches the entry being read, NextMatch and PrevMatch move between the matches.
+	// Outline lists the entry's headings to jump to.
+	Search    key.Binding
+	NextMatch key.Binding
+	PrevMatch key.Binding
+	Outline   key.Binding
+	// EditorPreview shows a preview of the entry being written beside the editor, or hides it.
+	EditorPreview key.Binding
+	// Preview shows the list beside a preview of the highlighted entry, or on its own.
+	Preview key.Binding
+	// ToggleView switches between browsing the journal as a list and as a calendar.
+	ToggleView key.Binding
+	Enter      key.Binding
+	Edit       key.Binding
+	Delete     key.Binding
+	Back       key.Binding
+	Quit       key.Binding
+	ForceQuit  key.Binding
+	Save       key.Binding
+	Sync       key.Binding
+	// ExternalEdit opens the entry being read in $EDITOR, OpenEditor hands the editor's text to it.
+	ExternalEdit key.Binding
+	OpenEditor   key.Binding
+	// NextField and PrevField move between the inputs of a form.
+	NextField key.Binding
+	PrevField key.Binding
 }
 
 // Keymap reusable key mappings shared across models
@@ -46,6 +95,75 @@ var Keymap = keymap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "create"),
 	),
+	Template: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "create from template"),
+	),
+	Today: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "today's note"),
+	),
+	Fields: key.NewBinding(
+		// not f, which the list and the viewport page down with.
+		key.WithKeys("F"),
+		key.WithHelp("F", "fields"),
+	),
+	Mood: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "mood"),
+	),
+	Stats: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "stats"),
+	),
+	OnThisDay: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "on this day"),
+	),
+	Random: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "random memory"),
+	),
+	NewerEntry: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "newer entry"),
+	),
+	OlderEntry: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "older entry"),
+	),
+	Read: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "reading mode"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	PrevMatch: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	Outline: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "outline"),
+	),
+	EditorPreview: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "preview"),
+	),
+	Preview: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "preview"),
+	),
+	ToggleView: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "calendar"),
+	),
 	Enter: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "select"),
@@ -74,9 +192,30 @@ var Keymap = keymap{
 		key.WithKeys("ctrl+s"),
 		key.WithHelp("ctrl+s", "save contents"),
 	),
+	Sync: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sync"),
+	),
+	ExternalEdit: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "edit in $EDITOR"),
+	),
+	OpenEditor: key.NewBinding(
+		key.WithKeys("ctrl+o"),
+		key.WithHelp("ctrl+o", "open in $EDITOR"),
+	),
+	NextField: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab/↓", "next"),
+	),
+	PrevField: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab/↑", "previous"),
+	),
 }
 
-func getBasePath() (string, error) {
+// BasePath returns the directory the journal and its config are stored in.
+func BasePath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -84,3 +223,8 @@ func getBasePath() (string, error) {
 
 	return home + "/.jrnl", nil
 }
+
+// TemplateDir returns the directory template files are read from.
+func TemplateDir(basePath string) string {
+	return basePath + "/templates"
+}
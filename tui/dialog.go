@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dialogAction identifies what a dialog is asking about, so the model that opened it knows
+// what to do with the answer.
+type dialogAction int
+
+const (
+	deleteEntryAction dialogAction = iota
+	discardChangesAction
+	quitAction
+	recoverDraftAction
+	discardDraftAction
+)
+
+// dialogMsg is sent when a dialog is answered. Dismissing a dialog with esc is the same as cancelling it.
+type dialogMsg struct {
+	action    dialogAction
+	confirmed bool
+}
+
+var (
+	dialogStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			Padding(1, 2)
+	buttonStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Background(lipgloss.Color("238")).
+			Padding(0, 2).
+			MarginLeft(2)
+	activeButtonStyle = buttonStyle.Copy().
+				Foreground(lipgloss.Color("230")).
+				Background(lipgloss.Color("62")).
+				Underline(true)
+)
+
+var dialogKeys = struct {
+	Toggle  key.Binding
+	Select  key.Binding
+	Confirm key.Binding
+	Cancel  key.Binding
+}{
+	Toggle:  key.NewBinding(key.WithKeys("left", "right", "h", "l", "tab", "shift+tab")),
+	Select:  key.NewBinding(key.WithKeys("enter", " ")),
+	Confirm: key.NewBinding(key.WithKeys("y")),
+	Cancel:  key.NewBinding(key.WithKeys("n", "esc")),
+}
+
+// dialog is a modal confirm or cancel question. While it is open the model showing it hands
+// it every key press, and it answers with a dialogMsg. Focus starts on the cancel button so
+// an absent-minded enter never does anything destructive.
+type dialog struct {
+	action  dialogAction
+	message string
+	confirm string
+	cancel  string
+	focused bool
+	open    bool
+}
+
+// newDialog returns an open dialog asking message, with buttons labelled confirm and cancel.
+func newDialog(action dialogAction, message, confirm, cancel string) dialog {
+	return dialog{
+		action:  action,
+		message: message,
+		confirm: confirm,
+		cancel:  cancel,
+		open:    true,
+	}
+}
+
+// Update handles a key press, closing the dialog once it has been answered.
+func (d dialog) Update(msg tea.KeyMsg) (dialog, tea.Cmd) {
+	switch {
+	case key.Matches(msg, dialogKeys.Toggle):
+		d.focused = !d.focused
+		return d, nil
+	case key.Matches(msg, dialogKeys.Select):
+		return d.answer(d.focused)
+	case key.Matches(msg, dialogKeys.Confirm):
+		return d.answer(true)
+	case key.Matches(msg, dialogKeys.Cancel):
+		return d.answer(false)
+	}
+
+	return d, nil
+}
+
+func (d dialog) answer(confirmed bool) (dialog, tea.Cmd) {
+	d.open = false
+	action := d.action
+	return d, func() tea.Msg {
+		return dialogMsg{action: action, confirmed: confirmed}
+	}
+}
+
+// View renders the dialog centered in a width by height area.
+func (d dialog) View(width, height int) string {
+	cancel, confirm := activeButtonStyle.Render(d.cancel), buttonStyle.Render(d.confirm)
+	if d.focused {
+		cancel, confirm = buttonStyle.Render(d.cancel), activeButtonStyle.Render(d.confirm)
+	}
+
+	message := lipgloss.NewStyle().Width(min(50, max(20, width-10))).Render(d.message)
+	buttons := lipgloss.JoinHorizontal(lipgloss.Top, cancel, confirm)
+	body := lipgloss.JoinVertical(lipgloss.Right, message, "", buttons, "", HelpStyle("←/→ choose • enter select • y/n • esc cancel"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, dialogStyle.Render(body))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/actatum/jrnl"
 	"github.com/charmbracelet/bubbles/key"
@@ -11,38 +14,117 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+const (
+	// autosaveInterval is how often the editor saves unsaved changes as a draft.
+	autosaveInterval = 5 * time.Second
+	// previewDelay is how long the text has to stop changing for before the preview catches up.
+	previewDelay = 300 * time.Millisecond
+)
+
+// editorSessions counts the editors opened, so an editor can tell its own autosave ticks
+// from those of an editor that was open before it.
+var editorSessions int
+
 // EditorUI implements tea.Model.
 type EditorUI struct {
+	// entry is the entry as last saved, updatedEntry holds the changes to it.
 	entry        entryItem
 	updatedEntry entryItem
 	textarea     textarea.Model
 	jr           *jrnl.Journal
 	create       bool
+	saving       bool
+	draft        jrnl.Draft
+	session      int
+	dialog       dialog
 	quitting     bool
+	// mood asks for the writer's mood the first time an entry without one is saved.
+	mood      moodPicker
+	moodAsked bool
+	// preview shows the entry rendered beside the text when the editor is split. previewVersion
+	// counts the changes to the text, so the preview is only rendered for the latest.
+	preview        entryPreview
+	previewVersion int
 }
 
 // InitEditorUI ...
 func InitEditorUI(e entryItem, jr *jrnl.Journal, create bool) tea.Model {
+	return newEditorUI(e, jr, create)
+}
+
+// newEditorUI is InitEditorUI for the editors that are opened on something other than e's content.
+func newEditorUI(e entryItem, jr *jrnl.Journal, create bool) EditorUI {
+	editorSessions++
 	ui := EditorUI{
 		entry:        e,
 		updatedEntry: e,
 		textarea:     textarea.New(),
 		jr:           jr,
 		create:       create,
+		draft:        jrnl.Draft{EntryID: e.ID},
+		session:      editorSessions,
 	}
 
 	ui.textarea.SetValue(e.Content)
 	ui.textarea.CharLimit = 50000
 	ui.textarea.Focus()
-	ui.textarea.SetWidth(WindowSize.Width)
-	ui.textarea.SetHeight(WindowSize.Height - ui.verticalMarginHeight())
+	if err := ui.layout(); err != nil {
+		log.Printf("ERROR: %s\n", err)
+	}
 
 	return ui
 }
 
+// initDraftEditorUI opens the editor on a recovered draft of e, or of a new entry if e is the zero entryItem.
+func initDraftEditorUI(e entryItem, jr *jrnl.Journal, d jrnl.Draft) tea.Model {
+	ui := newEditorUI(e, jr, e.ID == 0)
+	d.EntryID = e.ID
+	ui.draft = d
+	ui.textarea.SetValue(d.Content)
+	ui.updatedEntry.Content = d.Content
+	if err := ui.renderPreview(); err != nil {
+		log.Printf("ERROR: %s\n", err)
+	}
+
+	return ui
+}
+
+// initDayEditorUI opens the editor the way daily notes keep to one entry per day: on the entry
+// already written on the day of e, today if e has no creation time, with e's content added to
+// the end of it, or on e as a new entry if nothing was written that day.
+func initDayEditorUI(e entryItem, jr *jrnl.Journal) (tea.Model, error) {
+	day := e.CreateTime
+	if day.IsZero() {
+		day = time.Now()
+	}
+	existing, err := jr.EntryForDay(day)
+	if errors.Is(err, jrnl.ErrEntryNotFound) {
+		return InitEditorUI(e, jr, true), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ui := newEditorUI(entryItem{existing}, jr, false)
+	if strings.TrimSpace(e.Content) == "" {
+		return ui, nil
+	}
+	content := e.Content
+	if existing.Content != "" {
+		content = strings.TrimRight(existing.Content, "\n") + "\n\n" + content
+	}
+	ui.textarea.SetValue(content)
+	ui.updatedEntry.Content = content
+	if err = ui.renderPreview(); err != nil {
+		return nil, err
+	}
+
+	return ui, nil
+}
+
 // Init ...
 func (ui EditorUI) Init() tea.Cmd {
-	return textarea.Blink
+	return tea.Batch(textarea.Blink, autosaveCmd(ui.session))
 }
 
 // Update ...
@@ -54,37 +136,116 @@ func (ui EditorUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case createEntryMsg:
-		m, err := InitEntryUI(msg.entry, ui.jr)
-		if err != nil {
-			return ui, func() tea.Msg { return errMsg{err} }
-		}
-		return m, tea.Batch(cmds...)
+		cmds = append(cmds, ui.saved(msg.entry))
 	case editEntryMsg:
-		m, err := InitEntryUI(msg.entry, ui.jr)
-		if err != nil {
+		cmds = append(cmds, ui.saved(msg.entry))
+	case autosaveMsg:
+		if msg.session != ui.session {
+			return ui, nil
+		}
+		cmds = append(cmds, autosaveCmd(ui.session))
+		if ui.dirty() && ui.textarea.Value() != ui.draft.Content {
+			cmds = append(cmds, ui.saveDraftCmd())
+		}
+	case draftSavedMsg:
+		if msg.session != ui.session {
+			return ui, nil
+		}
+		// an autosave can finish after the entry was saved, recreating a draft saved() deleted
+		// or never knew of, which would be offered for recovery later.
+		if !ui.dirty() {
+			ui.draft = jrnl.Draft{EntryID: ui.entry.ID}
+			return ui, deleteDraftCmd(msg.draft.ID, ui.jr)
+		}
+		// the draft may have been taken before a new entry was created.
+		msg.draft.EntryID = ui.draft.EntryID
+		ui.draft = msg.draft
+	case previewTickMsg:
+		if msg.session != ui.session || msg.version != ui.previewVersion {
+			return ui, nil
+		}
+		if err := ui.renderPreview(); err != nil {
 			return ui, func() tea.Msg { return errMsg{err} }
 		}
-		return m, tea.Batch(cmds...)
+	case externalEditMsg:
+		if msg.err != nil {
+			return ui, func() tea.Msg { return errMsg{msg.err} }
+		}
+		ui.textarea.SetValue(msg.content)
+		ui.updatedEntry.Content = msg.content
+		cmds = append(cmds, ui.textChanged())
+		if ui.dirty() && (!ui.create || strings.TrimSpace(msg.content) != "") {
+			cmds = append(cmds, ui.save())
+		}
+	case moodMsg:
+		ui.moodAsked = true
+		if msg.mood != 0 {
+			ui.updatedEntry.Mood = msg.mood
+		}
+		cmds = append(cmds, ui.save())
+	case dialogMsg:
+		switch {
+		case msg.action == discardChangesAction && msg.confirmed:
+			if ui.draft.ID != "" {
+				cmds = append(cmds, deleteDraftCmd(ui.draft.ID, ui.jr))
+			}
+			m, leaveCmd := ui.leave()
+			return m, tea.Batch(append(cmds, leaveCmd)...)
+		case msg.action == quitAction && msg.confirmed:
+			return ui.quit()
+		}
 	case tea.KeyMsg:
+		if ui.mood.open {
+			if key.Matches(msg, Keymap.ForceQuit) {
+				return ui.quit()
+			}
+			ui.mood, cmd = ui.mood.Update(msg)
+			return ui, cmd
+		}
+		if ui.dialog.open {
+			// a second ctrl+c while asked whether to quit quits.
+			if key.Matches(msg, Keymap.ForceQuit) {
+				return ui.quit()
+			}
+			ui.dialog, cmd = ui.dialog.Update(msg)
+			return ui, cmd
+		}
+
 		switch {
 		case key.Matches(msg, Keymap.Back):
-			m, err := InitEntryUI(ui.entry, ui.jr)
-			if err != nil {
-				return ui, func() tea.Msg { return errMsg{err} }
+			if ui.dirty() {
+				ui.dialog = newDialog(discardChangesAction, "Discard the changes you made since the last save?", "Discard", "Keep editing")
+				return ui, nil
 			}
-			return m, tea.Batch(cmds...)
+			return ui.leave()
 		case key.Matches(msg, Keymap.ForceQuit):
+			if ui.dirty() {
+				ui.dialog = newDialog(quitAction, "Quit with unsaved changes? They will be kept as a draft you can recover next time.", "Quit", "Keep editing")
+				return ui, nil
+			}
 			return ui, tea.Quit
 		case key.Matches(msg, Keymap.Save):
-			if ui.create {
-				cmds = append(cmds, createEntryCmd(ui.updatedEntry.Content, ui.jr))
-			} else {
-				cmds = append(cmds, editEntryCmd(ui.updatedEntry, ui.jr))
+			if askMood && !ui.moodAsked && ui.updatedEntry.Mood == 0 {
+				ui.mood = newMoodPicker("How are you feeling?")
+				return ui, nil
+			}
+			cmds = append(cmds, ui.save())
+		case key.Matches(msg, Keymap.OpenEditor):
+			cmds = append(cmds, externalEditCmd(ui.textarea.Value()))
+		case key.Matches(msg, Keymap.EditorPreview):
+			editorPreview = !editorPreview
+			if err := ui.layout(); err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
 			}
 		default:
+			before := ui.textarea.Value()
 			ui.textarea, cmd = ui.textarea.Update(msg)
 			ui.updatedEntry.Content = ui.textarea.Value()
 			cmds = append(cmds, cmd)
+			if ui.textarea.Value() != before {
+				cmds = append(cmds, ui.textChanged())
+			}
+			ui.syncPreview()
 			if !ui.textarea.Focused() {
 				cmd = ui.textarea.Focus()
 				cmds = append(cmds, cmd)
@@ -92,27 +253,176 @@ func (ui EditorUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case tea.WindowSizeMsg:
 		WindowSize = msg
-		ui.textarea.SetWidth(msg.Width)
-		ui.textarea.SetHeight(msg.Height - ui.verticalMarginHeight())
+		if err := ui.layout(); err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
 	case errMsg:
+		ui.saving = false
 		log.Printf("ERROR: %s", msg.Error())
+	default:
+		ui.textarea, cmd = ui.textarea.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return ui, tea.Batch(cmds...)
 }
 
+// leave goes back to the entry, or to the journal if it was never saved.
+func (ui EditorUI) leave() (tea.Model, tea.Cmd) {
+	if ui.entry.ID == 0 {
+		m, err := initHomeUI(ui.jr)
+		if err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
+		return m, m.Init()
+	}
+
+	m, err := InitEntryUI(ui.entry, ui.jr)
+	if err != nil {
+		return ui, func() tea.Msg { return errMsg{err} }
+	}
+	return m, nil
+}
+
+// quit exits, keeping any unsaved changes as a draft. The draft is saved straight away as a
+// command might not get to run before the program exits.
+func (ui EditorUI) quit() (tea.Model, tea.Cmd) {
+	if ui.dirty() {
+		d := ui.draft
+		d.Content = ui.textarea.Value()
+		if _, err := ui.jr.SaveDraft(d); err != nil {
+			log.Printf("ERROR: saving draft: %v\n", err)
+		}
+	}
+	ui.quitting = true
+
+	return ui, tea.Quit
+}
+
+// save saves the changes to the entry, creating it the first time a new entry is saved. While
+// that first save is in flight further saves are ignored so the entry isn't created twice.
+func (ui *EditorUI) save() tea.Cmd {
+	if ui.saving {
+		return nil
+	}
+	if ui.create {
+		ui.saving = true
+		return createEntryCmd(ui.updatedEntry, ui.jr)
+	}
+
+	return editEntryCmd(ui.updatedEntry, ui.jr)
+}
+
+// saved records that e has been saved, so a new entry is edited from now on, and deletes the
+// draft unless there have been more changes since.
+func (ui *EditorUI) saved(e entryItem) tea.Cmd {
+	ui.entry = e
+	ui.updatedEntry = e
+	ui.updatedEntry.Content = ui.textarea.Value()
+	ui.create = false
+	ui.saving = false
+	ui.draft.EntryID = e.ID
+
+	if ui.dirty() || ui.draft.ID == "" {
+		return nil
+	}
+	id := ui.draft.ID
+	ui.draft = jrnl.Draft{EntryID: e.ID}
+
+	return deleteDraftCmd(id, ui.jr)
+}
+
+// dirty reports whether the text has changed since the entry was last saved.
+func (ui EditorUI) dirty() bool {
+	return ui.textarea.Value() != ui.entry.Content
+}
+
+func (ui EditorUI) saveDraftCmd() tea.Cmd {
+	d := ui.draft
+	d.Content = ui.textarea.Value()
+	return saveDraftCmd(d, ui.session, ui.jr)
+}
+
 // View ...
 func (ui EditorUI) View() string {
 	if ui.quitting {
 		return ""
 	}
+	if ui.dialog.open {
+		return ui.dialog.View(WindowSize.Width, WindowSize.Height)
+	}
+	if ui.mood.open {
+		return ui.mood.View(WindowSize.Width, WindowSize.Height)
+	}
 
+	if ui.previewing() {
+		return fmt.Sprintf("%s\n%s", lipgloss.JoinHorizontal(lipgloss.Top, ui.textarea.View(), ui.preview.View()), ui.helpView())
+	}
 	return fmt.Sprintf("%s\n%s", ui.textarea.View(), ui.helpView())
 }
 
+// previewing is whether the preview is shown beside the text, which it is when asked for and
+// there's the room.
+func (ui EditorUI) previewing() bool {
+	return editorPreview && WindowSize.Width >= splitPaneMinWidth
+}
+
+// layout sizes the text, and the preview beside it if the editor is split, to the window.
+func (ui *EditorUI) layout() error {
+	height := WindowSize.Height - ui.verticalMarginHeight()
+	if !ui.previewing() {
+		ui.textarea.SetWidth(WindowSize.Width)
+		ui.textarea.SetHeight(height)
+		return nil
+	}
+
+	ui.textarea.SetWidth(WindowSize.Width / 2)
+	ui.textarea.SetHeight(height)
+	if err := ui.preview.resize(WindowSize.Width-WindowSize.Width/2, height); err != nil {
+		return err
+	}
+	return ui.renderPreview()
+}
+
+// textChanged notes a change to the text, for the preview to catch up with once the changes stop.
+func (ui *EditorUI) textChanged() tea.Cmd {
+	if !ui.previewing() {
+		return nil
+	}
+	ui.previewVersion++
+	return previewTickCmd(ui.session, ui.previewVersion)
+}
+
+// renderPreview renders the text as it is now in the preview, if it's showing.
+func (ui *EditorUI) renderPreview() error {
+	if !ui.previewing() {
+		return nil
+	}
+
+	e := ui.updatedEntry
+	e.Content = ui.textarea.Value()
+	if err := ui.preview.render(e.markdown()); err != nil {
+		return err
+	}
+	ui.syncPreview()
+	return nil
+}
+
+// syncPreview scrolls the preview as far down as the cursor is down the text.
+func (ui *EditorUI) syncPreview() {
+	if !ui.previewing() {
+		return
+	}
+	fraction := 0.0
+	if n := ui.textarea.LineCount(); n > 1 {
+		fraction = float64(ui.textarea.Line()) / float64(n-1)
+	}
+	ui.preview.scrollTo(fraction)
+}
+
 func (ui EditorUI) helpView() string {
 	// TODO: use the keymaps to populate the help string
-	return HelpStyle("\n • ctrl+s save • esc back \n")
+	return HelpStyle("\n • ctrl+s save • ctrl+o open in $EDITOR • ctrl+r preview • esc back \n")
 }
 
 func (ui EditorUI) verticalMarginHeight() int {
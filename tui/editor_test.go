@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/actatum/jrnl"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEditorUI_DraftSavedAfterSave(t *testing.T) {
+	// the autosave's draftSavedMsg arrives after the entry is saved, and so does the
+	// SaveDraft behind it when saved() has already deleted the draft it knew of.
+	tests := []struct {
+		name string
+		// knownDraft is whether the editor had been told of the draft before the save.
+		knownDraft bool
+	}{
+		{name: "autosave sent before the entry was created", knownDraft: false},
+		{name: "autosave in flight when the draft was deleted", knownDraft: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jr := mustNewTestJournal(t)
+			ui := newEditorUI(entryItem{}, jr, true)
+			ui.textarea.SetValue("hello")
+
+			d, err := jr.SaveDraft(jrnl.Draft{Content: "hello"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.knownDraft {
+				ui = mustUpdate(t, ui, draftSavedMsg{session: ui.session, draft: d})
+			}
+
+			e, err := jr.CreateEntry("hello")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ui = mustUpdate(t, ui, createEntryMsg{entry: entryItem{e}})
+
+			// the autosave's Put lands after any delete and its message arrives last.
+			if d, err = jr.SaveDraft(d); err != nil {
+				t.Fatal(err)
+			}
+			ui = mustUpdate(t, ui, draftSavedMsg{session: ui.session, draft: d})
+
+			drafts, err := jr.ListDrafts()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(drafts) != 0 {
+				t.Errorf("drafts left after the entry was saved: %+v", drafts)
+			}
+			if ui.draft.ID != "" || ui.draft.EntryID != e.ID {
+				t.Errorf("editor's draft = %+v, want none for entry %d", ui.draft, e.ID)
+			}
+		})
+	}
+}
+
+func TestEditorUI_PreviewTick(t *testing.T) {
+	size, preview := WindowSize, editorPreview
+	t.Cleanup(func() { WindowSize, editorPreview = size, preview })
+	WindowSize = tea.WindowSizeMsg{Width: splitPaneMinWidth + 10, Height: 30}
+	editorPreview = true
+
+	jr := mustNewTestJournal(t)
+	ui := newEditorUI(entryItem{}, jr, true)
+	for _, r := range "ab" {
+		// the preview's tick isn't run, the test delivers them itself.
+		m, _ := ui.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		var ok bool
+		if ui, ok = m.(EditorUI); !ok {
+			t.Fatalf("Update() returned a %T, want the editor", m)
+		}
+	}
+	if ui.previewVersion != 2 {
+		t.Fatalf("previewVersion = %d, want 2 after two changes", ui.previewVersion)
+	}
+
+	previewed := func() bool {
+		return strings.Contains(ansiCodes.ReplaceAllString(ui.preview.View(), ""), "ab")
+	}
+	for _, msg := range []previewTickMsg{
+		{session: ui.session, version: 1},
+		{session: ui.session - 1, version: 2},
+	} {
+		ui = mustUpdate(t, ui, msg)
+		if previewed() {
+			t.Errorf("preview rendered for %+v, want only the latest change of this editor's text", msg)
+		}
+	}
+	if ui = mustUpdate(t, ui, previewTickMsg{session: ui.session, version: 2}); !previewed() {
+		t.Errorf("preview not rendered for the latest change:\n%s", ui.preview.View())
+	}
+}
+
+// mustUpdate hands msg to ui and runs the commands it returns, failing on an errMsg.
+func mustUpdate(tb testing.TB, ui EditorUI, msg tea.Msg) EditorUI {
+	tb.Helper()
+
+	m, cmd := ui.Update(msg)
+	mustRun(tb, cmd)
+	ui, ok := m.(EditorUI)
+	if !ok {
+		tb.Fatalf("Update() returned a %T, want the editor", m)
+	}
+	return ui
+}
+
+func mustRun(tb testing.TB, cmd tea.Cmd) {
+	tb.Helper()
+
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			mustRun(tb, c)
+		}
+	case errMsg:
+		tb.Fatal(msg.error)
+	}
+}
+
+// mustNewTestJournal returns an authenticated journal in a new file, closed when the test ends.
+func mustNewTestJournal(tb testing.TB) *jrnl.Journal {
+	tb.Helper()
+
+	jr, err := jrnl.NewJournal(filepath.Join(tb.TempDir(), "jrnl.db"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		if err := jr.Close(); err != nil {
+			tb.Error(err)
+		}
+	})
+	if err = jr.CreatePassword("password"); err != nil {
+		tb.Fatal(err)
+	}
+	if err = jr.Auth("password"); err != nil {
+		tb.Fatal(err)
+	}
+
+	return jr
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -42,31 +43,25 @@ type EntryUI struct {
 	renderer *glamour.TermRenderer
 	ready    bool
 	quitting bool
+	mood     moodPicker
+	// rendered is the entry as glamour renders it, before any search matches are highlighted.
+	rendered string
+	search   entrySearch
+	outline  outlinePanel
 }
 
 // InitEntryUI ...
 func InitEntryUI(e entryItem, jr *jrnl.Journal) (tea.Model, error) {
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(WindowSize.Width-5),
-		glamour.WithEmoji(),
-	)
-	if err != nil {
-		return nil, err
-	}
-
 	ui := EntryUI{
-		entry:    e,
-		jr:       jr,
-		renderer: renderer,
+		entry:   e,
+		jr:      jr,
+		outline: outlinePanel{headings: jrnl.Outline(e.markdown())},
 	}
 
 	ui.viewport = viewport.New(WindowSize.Width, WindowSize.Height-ui.verticalMarginHeight())
-	str, err := renderer.Render(e.Content)
-	if err != nil {
+	if err := ui.render(); err != nil {
 		return ui, err
 	}
-	ui.viewport.SetContent(str)
 
 	return ui, nil
 }
@@ -84,19 +79,104 @@ func (ui EntryUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	)
 
 	switch msg := msg.(type) {
+	case externalEditMsg:
+		if msg.err != nil {
+			return ui, func() tea.Msg { return errMsg{msg.err} }
+		}
+		if msg.content != ui.entry.Content {
+			e := ui.entry
+			e.Content = msg.content
+			cmds = append(cmds, editEntryCmd(e, ui.jr))
+		}
+	case moodMsg:
+		if msg.mood != 0 && msg.mood != ui.entry.Mood {
+			cmds = append(cmds, setMoodCmd(ui.entry.ID, msg.mood, ui.jr))
+		}
+	case editEntryMsg:
+		m, err := InitEntryUI(msg.entry, ui.jr)
+		if err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
+		return m.Update(WindowSize)
 	case tea.KeyMsg:
+		// nothing here is unsaved, so ctrl+c quits whatever is open, like the editor's mood picker.
+		if key.Matches(msg, Keymap.ForceQuit) {
+			return ui, tea.Quit
+		}
+		if ui.mood.open {
+			ui.mood, cmd = ui.mood.Update(msg)
+			return ui, cmd
+		}
+		if ui.search.typing {
+			return ui.updateSearch(msg)
+		}
+		if ui.outline.open {
+			var line int
+			ui.outline, line = ui.outline.Update(msg)
+			if line >= 0 {
+				ui.viewport.SetYOffset(line)
+			}
+			if !ui.outline.open {
+				if err := ui.render(); err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+			}
+			return ui, nil
+		}
+
 		switch {
 		case key.Matches(msg, Keymap.Quit):
 			return ui, tea.Quit
+		case key.Matches(msg, Keymap.Back) && ui.search.query != "":
+			ui.search = entrySearch{}
+			ui.highlight()
+			return ui, nil
+		case key.Matches(msg, Keymap.Search):
+			ui.search = newEntrySearch()
+			ui.search.input.Width = ui.viewport.Width - 3
+			return ui, ui.search.input.Focus()
+		case key.Matches(msg, Keymap.NextMatch) && ui.search.query != "":
+			ui.jumpTo(ui.search.next(true))
+			return ui, nil
+		case key.Matches(msg, Keymap.PrevMatch) && ui.search.query != "":
+			ui.jumpTo(ui.search.next(false))
+			return ui, nil
+		case key.Matches(msg, Keymap.Outline):
+			ui.outline.open = true
+			if err := ui.render(); err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return ui, nil
 		case key.Matches(msg, Keymap.Back):
-			m, err := InitJournalUI(ui.jr)
+			m, err := initHomeUI(ui.jr)
 			if err != nil {
 				return ui, func() tea.Msg { return errMsg{err} }
 			}
-			return m, tea.Batch(cmds...)
+			return m, tea.Batch(append(cmds, m.Init())...)
 		case key.Matches(msg, Keymap.Edit):
 			m := InitEditorUI(ui.entry, ui.jr, false)
-			return m, tea.Batch(cmds...)
+			return m, m.Init()
+		case key.Matches(msg, Keymap.Mood):
+			ui.mood = newMoodPicker("How were you feeling?")
+			return ui, nil
+		case key.Matches(msg, Keymap.Fields):
+			m, err := initFieldsOrSchemaUI(ui.entry, ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m, m.Init()
+		case key.Matches(msg, Keymap.ExternalEdit):
+			cmds = append(cmds, externalEditCmd(ui.entry.Content))
+		case key.Matches(msg, Keymap.NewerEntry):
+			return ui.adjacent(true)
+		case key.Matches(msg, Keymap.OlderEntry):
+			return ui.adjacent(false)
+		case key.Matches(msg, Keymap.Read):
+			m, err := InitReadingUI(ui.entry, ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m, nil
 		}
 	case tea.WindowSizeMsg:
 		WindowSize = msg
@@ -108,23 +188,8 @@ func (ui EntryUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// we can initialize the viewport. The initial dimensions come in
 			// quickly, though asynchronously, which is why we wait for them
 			// here.
-			var err error
-			ui.renderer, err = glamour.NewTermRenderer(
-				glamour.WithAutoStyle(),
-				glamour.WithWordWrap(msg.Width-5),
-				glamour.WithEmoji(),
-			)
-			if err != nil {
-				return ui, func() tea.Msg { return errMsg{err} }
-			}
 			ui.viewport = viewport.New(msg.Width, msg.Height-ui.verticalMarginHeight())
-			ui.viewport.YPosition = headerHeight
 			ui.viewport.HighPerformanceRendering = useHighPerformanceRenderer
-			str, err := ui.renderer.Render(ui.entry.Content)
-			if err != nil {
-				return ui, func() tea.Msg { return errMsg{err} }
-			}
-			ui.viewport.SetContent(str)
 			ui.ready = true
 
 			// This is only necessary for high performance rendering, which in
@@ -133,23 +198,11 @@ func (ui EntryUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Render the viewport one line below the header.
 			ui.viewport.YPosition = headerHeight + 1
 		} else {
-			var err error
-			ui.renderer, err = glamour.NewTermRenderer(
-				glamour.WithAutoStyle(),
-				glamour.WithWordWrap(msg.Width-5),
-				glamour.WithEmoji(),
-			)
-			if err != nil {
-				return ui, func() tea.Msg { return errMsg{err} }
-			}
-			str, err := ui.renderer.Render(ui.entry.Content)
-			if err != nil {
-				return ui, func() tea.Msg { return errMsg{err} }
-			}
-			ui.viewport.SetContent(str)
-			ui.viewport.Width = msg.Width
 			ui.viewport.Height = msg.Height - ui.verticalMarginHeight()
 		}
+		if err := ui.render(); err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
 	}
 
 	// Handle keyboard and mouse events in the viewport
@@ -164,25 +217,132 @@ func (ui EntryUI) View() string {
 	if ui.quitting {
 		return ""
 	}
+	if ui.mood.open {
+		return ui.mood.View(WindowSize.Width, WindowSize.Height)
+	}
+
+	page := ui.viewport.View()
+	if ui.outline.open {
+		page = lipgloss.JoinHorizontal(lipgloss.Top, page, ui.outline.View(ui.viewport.Height))
+	}
+	return fmt.Sprintf("%s\n%s\n%s\n%s", ui.headerView(), page, ui.footerView(), ui.helpView())
+}
+
+// render renders the entry to fit beside the outline if it's open, keeping the search matches highlighted.
+func (ui *EntryUI) render() error {
+	width := WindowSize.Width
+	if ui.outline.open {
+		width -= outlineWidth
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(width-5),
+		glamour.WithEmoji(),
+	)
+	if err != nil {
+		return err
+	}
+	ui.renderer = renderer
+
+	if ui.rendered, err = renderer.Render(ui.entry.markdown()); err != nil {
+		return err
+	}
+	ui.viewport.Width = width
+	ui.outline.lines = headingLines(ui.rendered, ui.outline.headings)
+	ui.highlight()
+
+	return nil
+}
+
+// highlight shows the rendered entry with the matches of the search highlighted.
+func (ui *EntryUI) highlight() {
+	var content string
+	content, ui.search.hits = highlightMatches(ui.rendered, ui.search.query, ui.search.current)
+	ui.viewport.SetContent(content)
+}
+
+// updateSearch hands a key press to the search being typed, searching once it's entered.
+func (ui EntryUI) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		ui.search = entrySearch{}
+		ui.highlight()
+		return ui, nil
+	case tea.KeyEnter:
+		ui.search.typing = false
+		ui.search.query = ui.search.input.Value()
+		ui.highlight()
+		ui.jumpTo(ui.search.first(ui.viewport.YOffset))
+		return ui, nil
+	}
 
-	return fmt.Sprintf("%s\n%s\n%s\n%s", ui.headerView(), ui.viewport.View(), ui.footerView(), ui.helpView())
+	var cmd tea.Cmd
+	ui.search.input, cmd = ui.search.input.Update(msg)
+	return ui, cmd
+}
+
+// jumpTo highlights the current match again and scrolls so that line, the line it's on, is in view.
+func (ui *EntryUI) jumpTo(line int) {
+	ui.highlight()
+	if line < 0 {
+		return
+	}
+	if line < ui.viewport.YOffset || line >= ui.viewport.YOffset+ui.viewport.Height {
+		ui.viewport.SetYOffset(max(0, line-ui.viewport.Height/3))
+	}
+}
+
+// adjacent opens the entry written just after this one, or with newer false the one just before,
+// staying on this one if there isn't one.
+func (ui EntryUI) adjacent(newer bool) (tea.Model, tea.Cmd) {
+	e, err := ui.jr.AdjacentEntry(ui.entry.ID, newer)
+	if errors.Is(err, jrnl.ErrEntryNotFound) {
+		return ui, nil
+	}
+	if err != nil {
+		return ui, func() tea.Msg { return errMsg{err} }
+	}
+
+	m, err := InitEntryUI(entryItem{e}, ui.jr)
+	if err != nil {
+		return ui, func() tea.Msg { return errMsg{err} }
+	}
+	return m.Update(WindowSize)
 }
 
 func (ui EntryUI) headerView() string {
-	title := titleStyle.Render(ui.entry.CreateTime.Format(journalTimeLayout))
+	title := titleStyle.Render(strings.TrimSpace(ui.entry.CreateTime.Format(journalTimeLayout) + " " + moodIcon(ui.entry.Mood)))
 	line := strings.Repeat("─", max(0, ui.viewport.Width-lipgloss.Width(title)))
 	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
 }
 
 func (ui EntryUI) footerView() string {
-	info := infoStyle.Render(fmt.Sprintf("%3.f%%", ui.viewport.ScrollPercent()*100))
+	status := fmt.Sprintf("%3.f%%", ui.viewport.ScrollPercent()*100)
+	switch {
+	case ui.search.query != "" && len(ui.search.hits) == 0:
+		status = "no matches • " + status
+	case ui.search.query != "":
+		status = fmt.Sprintf("match %d of %d • %s", ui.search.current+1, len(ui.search.hits), status)
+	}
+	info := infoStyle.Render(status)
 	line := strings.Repeat("─", max(0, ui.viewport.Width-lipgloss.Width(info)))
 	return lipgloss.JoinHorizontal(lipgloss.Center, line, info)
 }
 
+// helpView is two lines of help whatever is going on, so the viewport keeps its height.
 func (ui EntryUI) helpView() string {
+	switch {
+	case ui.search.typing:
+		return "\n " + ui.search.input.View() + "\n" + HelpStyle(" • enter search • esc cancel\n")
+	case ui.outline.open:
+		return HelpStyle("\n • ↑/k up • ↓/j down • enter jump to heading\n • esc/o close outline\n")
+	case ui.search.query != "":
+		return HelpStyle("\n • n/N next/previous match • / search again\n • esc clear search • q quit\n")
+	}
 	// TODO: use the keymaps to populate the help string
-	return HelpStyle("\n • ↑/k up • ↓/j down • e edit • esc back • q quit\n")
+	return HelpStyle("\n • ↑/k up • ↓/j down • n/p newer/older entry • / search • o outline • R read\n" +
+		" • e edit • E edit in $EDITOR • F fields • m mood • esc back • q quit\n")
 }
 
 func (ui EntryUI) verticalMarginHeight() int {
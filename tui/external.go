@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/actatum/jrnl"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// externalEditMsg carries the text written in the external editor once it exits.
+type externalEditMsg struct {
+	content string
+	err     error
+}
+
+// externalEditCmd suspends the TUI and opens content in the user's editor, resuming it with an
+// externalEditMsg once the editor exits.
+func externalEditCmd(content string) tea.Cmd {
+	c, finish, err := editorProcess(content)
+	if err != nil {
+		return func() tea.Msg { return errMsg{err} }
+	}
+
+	return tea.ExecProcess(c, func(runErr error) tea.Msg {
+		text, ferr := finish(runErr)
+		return externalEditMsg{content: text, err: ferr}
+	})
+}
+
+// EditText opens content in the user's editor, attached to the terminal, and returns what
+// they wrote once the editor exits.
+func EditText(content string) (string, error) {
+	c, finish, err := editorProcess(content)
+	if err != nil {
+		return "", err
+	}
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	return finish(c.Run())
+}
+
+// editorProcess prepares the user's editor to edit content. The text is passed through a
+// temporary file that only the user can read, kept off the disk where possible. finish must
+// be called with the editor's exit error once it's done, it reads the text back and shreds
+// the file. GUI editors have to be told to wait for the file to be closed, e.g.
+// EDITOR="code --wait".
+func editorProcess(content string) (*exec.Cmd, func(runErr error) (string, error), error) {
+	path, err := jrnl.TempPlaintextFile("jrnl-*.md", content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args := editorCommand()
+	c := exec.Command(args[0], append(args[1:], path)...)
+
+	finish := func(runErr error) (text string, err error) {
+		if runErr != nil {
+			err = fmt.Errorf("running %s: %w", args[0], runErr)
+		} else {
+			var data []byte
+			data, err = os.ReadFile(path)
+			// editors end the file with a newline the textarea never adds.
+			text = strings.TrimSuffix(string(data), "\n")
+		}
+
+		if serr := jrnl.ShredFile(path); serr != nil && err == nil {
+			err = serr
+		}
+
+		return text, err
+	}
+
+	return c, finish, nil
+}
+
+// editorCommand returns the user's editor command from $VISUAL or $EDITOR, split into its
+// program and arguments, falling back to vi.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if args := strings.Fields(os.Getenv(env)); len(args) > 0 {
+			return args
+		}
+	}
+
+	return []string{"vi"}
+}
@@ -0,0 +1,286 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var fieldLabelStyle = lipgloss.NewStyle().Bold(true)
+
+// FieldsUI implements tea.Model. It is a form for the typed fields of an entry, filled in
+// before the editor opens on a new entry or from the viewer for an existing one.
+type FieldsUI struct {
+	entry  entryItem
+	schema jrnl.Schema
+	inputs []textinput.Model
+	focus  int
+	err    error
+	jr     *jrnl.Journal
+}
+
+// InitFieldsUI opens the form for the fields of e following s. e is a new entry if its ID is 0.
+func InitFieldsUI(e entryItem, s jrnl.Schema, jr *jrnl.Journal) tea.Model {
+	ui := FieldsUI{
+		entry:  e,
+		schema: s,
+		jr:     jr,
+	}
+
+	for _, f := range s.Fields {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Placeholder = f.Hint()
+		if e.Schema == s.Name {
+			input.SetValue(e.Fields[f.Name])
+		}
+		ui.inputs = append(ui.inputs, input)
+	}
+	ui.inputs[0].Focus()
+
+	return ui
+}
+
+// Init ...
+func (ui FieldsUI) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update ...
+func (ui FieldsUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		WindowSize = msg
+	case editEntryMsg:
+		m, err := InitEntryUI(msg.entry, ui.jr)
+		if err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
+		return m.Update(WindowSize)
+	case errMsg:
+		ui.err = msg.error
+		log.Printf("ERROR: %s\n", msg.Error())
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, Keymap.ForceQuit):
+			return ui, tea.Quit
+		case key.Matches(msg, Keymap.Back):
+			return leaveFields(ui, ui.entry, ui.jr)
+		case key.Matches(msg, Keymap.Save),
+			key.Matches(msg, Keymap.Enter) && ui.focus == len(ui.inputs)-1:
+			return ui.submit()
+		case key.Matches(msg, Keymap.NextField), key.Matches(msg, Keymap.Enter):
+			return ui, ui.focusOn(ui.focus + 1)
+		case key.Matches(msg, Keymap.PrevField):
+			return ui, ui.focusOn(ui.focus - 1)
+		}
+	}
+
+	ui.inputs[ui.focus], cmd = ui.inputs[ui.focus].Update(msg)
+	return ui, cmd
+}
+
+// focusOn moves the cursor to the i'th field, wrapping around at either end.
+func (ui *FieldsUI) focusOn(i int) tea.Cmd {
+	ui.inputs[ui.focus].Blur()
+	ui.focus = (i + len(ui.inputs)) % len(ui.inputs)
+	return ui.inputs[ui.focus].Focus()
+}
+
+// submit checks the values and opens the editor on a new entry, or saves them to an existing one.
+func (ui FieldsUI) submit() (tea.Model, tea.Cmd) {
+	values := make(map[string]string, len(ui.inputs))
+	for i, f := range ui.schema.Fields {
+		values[f.Name] = ui.inputs[i].Value()
+	}
+	fields, err := ui.schema.Parse(values)
+	if err != nil {
+		ui.err = err
+		return ui, nil
+	}
+
+	if ui.entry.ID == 0 {
+		e := ui.entry
+		e.Schema, e.Fields = ui.schema.Name, fields
+		m := InitEditorUI(e, ui.jr, true)
+		return m, m.Init()
+	}
+
+	return ui, setEntryFieldsCmd(ui.entry.ID, ui.schema.Name, fields, ui.jr)
+}
+
+// leaveFields goes back from the form or the schema picker, ui, to e, or to the journal if e is a new entry.
+func leaveFields(ui tea.Model, e entryItem, jr *jrnl.Journal) (tea.Model, tea.Cmd) {
+	if e.ID == 0 {
+		m, err := initHomeUI(jr)
+		if err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
+		return m, m.Init()
+	}
+
+	m, err := InitEntryUI(e, jr)
+	if err != nil {
+		return ui, func() tea.Msg { return errMsg{err} }
+	}
+	return m.Update(WindowSize)
+}
+
+// View returns the text UI to be output to the terminal.
+func (ui FieldsUI) View() string {
+	width := 0
+	for _, f := range ui.schema.Fields {
+		width = max(width, lipgloss.Width(f.Name))
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(ui.schema.Name) + "\n\n")
+	for i, f := range ui.schema.Fields {
+		cursor := "  "
+		if i == ui.focus {
+			cursor = "> "
+		}
+		label := fieldLabelStyle.Render(f.Name + strings.Repeat(" ", width-lipgloss.Width(f.Name)))
+		fmt.Fprintf(&b, "%s%s  %s\n", cursor, label, ui.inputs[i].View())
+	}
+	if ui.err != nil {
+		b.WriteString("\n" + ErrStyle(ui.err.Error()) + "\n")
+	}
+
+	next := "enter/ctrl+s open the editor"
+	if ui.entry.ID != 0 {
+		next = "enter/ctrl+s save"
+	}
+	b.WriteString(HelpStyle("\n • tab/↓ next • shift+tab/↑ previous • " + next + " • esc back\n"))
+
+	return DocStyle.Render(b.String())
+}
+
+// SchemaUI implements tea.Model. It lists the schemas to pick the one an entry's fields follow.
+type SchemaUI struct {
+	schemaList list.Model
+	entry      entryItem
+	jr         *jrnl.Journal
+}
+
+// InitSchemaUI initializes the schema picker for e, a new entry if its ID is 0.
+func InitSchemaUI(e entryItem, jr *jrnl.Journal) (tea.Model, error) {
+	schemas, err := jr.ListSchemas()
+	if err != nil {
+		return nil, err
+	}
+	if len(schemas) == 0 {
+		return nil, errors.New("there are no schemas yet, add one with jrnl schema save")
+	}
+
+	items := make([]list.Item, 0, len(schemas))
+	for _, s := range schemas {
+		items = append(items, schemaItem{s})
+	}
+
+	ui := SchemaUI{
+		schemaList: list.New(items, list.NewDefaultDelegate(), 0, 0),
+		entry:      e,
+		jr:         jr,
+	}
+	ui.schemaList.Title = "Fields from schema"
+	top, right, bottom, left := DocStyle.GetMargin()
+	ui.schemaList.SetSize(WindowSize.Width-left-right, WindowSize.Height-top-bottom-1)
+
+	return ui, nil
+}
+
+// initFieldsOrSchemaUI opens the form on the schema e follows, or the schema picker if it
+// doesn't follow one that still exists.
+func initFieldsOrSchemaUI(e entryItem, jr *jrnl.Journal) (tea.Model, error) {
+	if e.Schema != "" {
+		s, err := jr.GetSchema(e.Schema)
+		if err == nil {
+			return InitFieldsUI(e, s, jr), nil
+		}
+		if !errors.Is(err, jrnl.ErrSchemaNotFound) {
+			return nil, err
+		}
+	}
+
+	return InitSchemaUI(e, jr)
+}
+
+// Init ...
+func (ui SchemaUI) Init() tea.Cmd {
+	return nil
+}
+
+// Update ...
+func (ui SchemaUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		WindowSize = msg
+		top, right, bottom, left := DocStyle.GetMargin()
+		ui.schemaList.SetSize(msg.Width-left-right, msg.Height-top-bottom-1)
+	case errMsg:
+		log.Printf("ERROR: %s\n", msg.Error())
+	case tea.KeyMsg:
+		// keys go to the filter while it's being typed.
+		if ui.schemaList.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, Keymap.ForceQuit):
+			return ui, tea.Quit
+		case key.Matches(msg, Keymap.Back):
+			return leaveFields(ui, ui.entry, ui.jr)
+		case key.Matches(msg, Keymap.Enter):
+			s, ok := ui.schemaList.SelectedItem().(schemaItem)
+			if !ok {
+				return ui, func() tea.Msg {
+					return errMsg{fmt.Errorf("failed type assertion on schemaList item")}
+				}
+			}
+			m := InitFieldsUI(ui.entry, s.Schema, ui.jr)
+			return m, m.Init()
+		}
+	}
+
+	ui.schemaList, cmd = ui.schemaList.Update(msg)
+	return ui, cmd
+}
+
+// View returns the text UI to be output to the terminal.
+func (ui SchemaUI) View() string {
+	return DocStyle.Render(ui.schemaList.View() + "\n")
+}
+
+type schemaItem struct {
+	jrnl.Schema
+}
+
+func (i schemaItem) Title() string {
+	return i.Name
+}
+
+// Description lists the schema's fields.
+func (i schemaItem) Description() string {
+	names := make([]string, 0, len(i.Fields))
+	for _, f := range i.Fields {
+		names = append(names, f.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (i schemaItem) FilterValue() string {
+	return i.Name
+}
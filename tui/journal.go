@@ -1,16 +1,18 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/actatum/jrnl"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
-	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // SelectMsg the message to change the view to the selected entry.
@@ -21,44 +23,97 @@ type SelectMsg struct {
 // JournalUI implements tea.Model.
 type JournalUI struct {
 	entryList list.Model
-	input     textinput.Model
+	dialog    dialog
 	quitting  bool
 	jr        *jrnl.Journal
+	// deleting is the entry the delete dialog is asking about.
+	deleting entryItem
+	// drafts are the unsaved drafts found at startup that are still to be asked about.
+	drafts []jrnl.Draft
+	// day is the start of the day the list is limited to, when it was opened from the calendar.
+	day time.Time
+	// preview shows the highlighted entry beside the list when the list is split.
+	preview entryPreview
+}
+
+// initHomeUI initializes the view the journal is browsed in, the calendar or the list.
+func initHomeUI(jr *jrnl.Journal) (tea.Model, error) {
+	if calendarView {
+		return InitCalendarUI(jr)
+	}
+	return InitJournalUI(jr)
 }
 
 // InitJournalUI initializes the journalui model.
 func InitJournalUI(jr *jrnl.Journal) (tea.Model, error) {
-	input := textinput.New()
-	input.Prompt = "> "
-	input.Placeholder = "..."
-	input.Width = 50
-
 	items, err := newEntryList(jr)
 	if err != nil {
 		return nil, err
 	}
 
 	ui := JournalUI{entryList: list.New(items, list.NewDefaultDelegate(), 0, 0),
-		input: input,
-		jr:    jr,
+		jr: jr,
 	}
 
-	ui.entryList.Title = "Journal Entries"
+	ui.entryList.Title = journalTitle(jr)
 	ui.entryList.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{
+		bindings := []key.Binding{
 			Keymap.Create,
+			Keymap.Template,
+			Keymap.Today,
+			Keymap.Fields,
+			Keymap.Stats,
+			Keymap.OnThisDay,
+			Keymap.Random,
+			Keymap.ToggleView,
+			Keymap.Preview,
+			Keymap.Read,
 			Keymap.Delete,
 		}
+		if jr.SyncEnabled() {
+			bindings = append(bindings, Keymap.Sync)
+		}
+		return bindings
+	}
+	if err = ui.layout(); err != nil {
+		return nil, err
 	}
-	top, right, bottom, left := DocStyle.GetMargin()
-	ui.entryList.SetSize(WindowSize.Width-left-right, WindowSize.Height-top-bottom-1)
 
 	return ui, nil
 }
 
+// initDayUI initializes the list with only the entries written on day.
+func initDayUI(jr *jrnl.Journal, day time.Time) (tea.Model, error) {
+	m, err := InitJournalUI(jr)
+	if err != nil {
+		return nil, err
+	}
+
+	ui, ok := m.(JournalUI)
+	if !ok {
+		return nil, fmt.Errorf("failed type assertion on the journal list")
+	}
+	ui.day = startOfDay(day)
+	ui.entryList.Title = "Entries on " + ui.day.Format("Mon, 02 Jan 2006")
+	ui.entryList.SetItems(ui.onDay(ui.entryList.Items()))
+	if err = ui.showPreview(); err != nil {
+		return nil, err
+	}
+	return ui, nil
+}
+
 // Init ...
 func (ui JournalUI) Init() tea.Cmd {
-	return nil
+	if listStarted {
+		return nil
+	}
+	listStarted = true
+
+	cmds := []tea.Cmd{listDraftsCmd(ui.jr)}
+	if ui.jr.SyncEnabled() {
+		cmds = append(cmds, syncStatusCmd(ui.jr))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update ...
@@ -69,42 +124,110 @@ func (ui JournalUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		WindowSize = msg
-		top, right, bottom, left := DocStyle.GetMargin()
-		ui.entryList.SetSize(msg.Width-left-right, msg.Height-top-bottom-1)
+		if err := ui.layout(); err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
 	case updateEntryListMsg:
 		entries, err := ui.jr.ListEntries()
 		if err != nil {
 			return ui, func() tea.Msg { return errMsg{err} }
 		}
-		items := entriesToItems(entries)
+		items := ui.onDay(entriesToItems(entries))
 		ui.entryList.SetItems(items)
+		if ui.jr.SyncEnabled() {
+			cmds = append(cmds, syncStatusCmd(ui.jr))
+		}
+	case syncStatusMsg:
+		// a list limited to a day keeps the day as its title.
+		if ui.day.IsZero() {
+			ui.entryList.Title = journalTitle(ui.jr)
+		}
+	case draftsMsg:
+		ui.drafts = msg.drafts
+		ui.promptDraft()
+	case dialogMsg:
+		return ui.answered(msg)
 	case errMsg:
 		log.Printf("ERROR: %s\n", msg.Error())
 	case tea.KeyMsg:
-		if ui.input.Focused() {
-			switch {
-			case key.Matches(msg, Keymap.Back):
-				ui.input.SetValue("")
-				ui.input.Blur()
-			case key.Matches(msg, Keymap.Enter):
-				if strings.ToLower(ui.input.Value()) == "delete" {
-					cmds = append(cmds, deleteEntryCmd(ui.getActiveEntryID(), ui.jr))
-					ui.input.SetValue("")
-					ui.input.Blur()
-				} else {
-					ui.input.SetValue("")
-					ui.input.Blur()
-				}
-			}
-			ui.input, cmd = ui.input.Update(msg)
+		if ui.dialog.open {
+			ui.dialog, cmd = ui.dialog.Update(msg)
 			cmds = append(cmds, cmd)
 		} else {
 			switch {
+			case ui.entryList.FilterState() == list.Filtering:
+				// keys go to the filter while it's being typed.
+				ui.entryList, cmd = ui.entryList.Update(msg)
 			case key.Matches(msg, Keymap.Quit):
 				ui.quitting = true
 				return ui, tea.Quit
+			case key.Matches(msg, Keymap.Back) && !ui.day.IsZero() && ui.entryList.FilterState() == list.Unfiltered:
+				m, err := initCalendarUIAt(ui.jr, ui.day)
+				if err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+				return m, nil
+			case key.Matches(msg, Keymap.ToggleView):
+				calendarView = true
+				m, err := initCalendarUIAt(ui.jr, ui.selectedDay())
+				if err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+				return m, nil
+			case key.Matches(msg, Keymap.Preview):
+				splitPane = !splitPane
+				if err := ui.layout(); err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+			case key.Matches(msg, Keymap.Today), key.Matches(msg, Keymap.Create) && dailyNotes:
+				return ui.openToday()
 			case key.Matches(msg, Keymap.Create):
-				return InitEditorUI(entryItem{}, ui.jr, true), tea.Batch(cmds...)
+				m := InitEditorUI(entryItem{}, ui.jr, true)
+				return m, m.Init()
+			case key.Matches(msg, Keymap.Template):
+				m, err := InitTemplateUI(ui.jr)
+				if err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+				return m, nil
+			case key.Matches(msg, Keymap.Stats):
+				m := InitStatsUI(ui.jr)
+				return m, m.Init()
+			case key.Matches(msg, Keymap.OnThisDay):
+				m, err := InitMemoriesUI(ui.jr)
+				if err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+				return m, nil
+			case key.Matches(msg, Keymap.Random):
+				return openRandomEntry(ui, ui.jr)
+			case key.Matches(msg, Keymap.Read):
+				e, _ := ui.entryList.SelectedItem().(entryItem)
+				if e.ID == 0 {
+					e.CreateTime = time.Now()
+				}
+				m, err := InitReadingUI(e, ui.jr)
+				if err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+				return m, nil
+			case key.Matches(msg, Keymap.Fields):
+				// daily notes keep to one entry a day, so once today's is written its fields are set.
+				e := entryItem{}
+				if dailyNotes {
+					today, err := ui.jr.EntryForDay(time.Now())
+					switch {
+					case err == nil:
+						e = entryItem{today}
+					case !errors.Is(err, jrnl.ErrEntryNotFound):
+						return ui, func() tea.Msg { return errMsg{err} }
+					}
+				}
+				m, err := initFieldsOrSchemaUI(e, ui.jr)
+				if err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+				return m, m.Init()
 			case key.Matches(msg, Keymap.Enter):
 				activeEntry, ok := ui.entryList.SelectedItem().(entryItem)
 				if !ok {
@@ -118,11 +241,15 @@ func (ui JournalUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				return entry.Update(WindowSize)
+			case key.Matches(msg, Keymap.Sync) && ui.jr.SyncEnabled():
+				ui.entryList.Title = journalTitle(ui.jr) + " • syncing..."
+				cmds = append(cmds, syncCmd(ui.jr))
 			case key.Matches(msg, Keymap.Delete):
-				items := ui.entryList.Items()
-				if len(items) > 0 {
-					ui.input.Placeholder = "Type 'delete' to delete this entry\n"
-					ui.input.Focus()
+				if e, ok := ui.entryList.SelectedItem().(entryItem); ok {
+					ui.deleting = e
+					ui.dialog = newDialog(deleteEntryAction,
+						fmt.Sprintf("Delete the entry from %s? This can't be undone.", e.CreateTime.Format(journalTimeLayout)),
+						"Delete", "Cancel")
 				}
 			default:
 				ui.entryList, cmd = ui.entryList.Update(msg)
@@ -131,6 +258,10 @@ func (ui JournalUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if err := ui.showPreview(); err != nil {
+		cmds = append(cmds, func() tea.Msg { return errMsg{err} })
+	}
+
 	return ui, tea.Batch(cmds...)
 }
 
@@ -139,17 +270,147 @@ func (ui JournalUI) View() string {
 	if ui.quitting {
 		return ""
 	}
-	if ui.input.Focused() {
-		return DocStyle.Render(ui.entryList.View() + "\n" + ui.input.View())
+	if ui.dialog.open {
+		return ui.dialog.View(WindowSize.Width, WindowSize.Height)
 	}
 
+	if ui.splitting() {
+		return DocStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, ui.entryList.View(), ui.preview.View()) + "\n")
+	}
 	return DocStyle.Render(ui.entryList.View() + "\n")
 }
 
-func (ui JournalUI) getActiveEntryID() int {
-	items := ui.entryList.Items()
-	activeItem := items[ui.entryList.Index()]
-	return activeItem.(entryItem).ID
+// splitting is whether the list is shown beside a preview, which it is when asked for and
+// there's the room.
+func (ui JournalUI) splitting() bool {
+	return splitPane && WindowSize.Width >= splitPaneMinWidth
+}
+
+// layout sizes the list, and the preview beside it if the list is split, to the window.
+func (ui *JournalUI) layout() error {
+	top, right, bottom, left := DocStyle.GetMargin()
+	width, height := WindowSize.Width-left-right, WindowSize.Height-top-bottom-1
+	if !ui.splitting() {
+		ui.entryList.SetSize(width, height)
+		return nil
+	}
+
+	listWidth := width * splitPaneListPercent / 100
+	ui.entryList.SetSize(listWidth, height)
+	if err := ui.preview.resize(width-listWidth, height); err != nil {
+		return err
+	}
+	return ui.showPreview()
+}
+
+// showPreview previews the highlighted entry if the list is split.
+func (ui *JournalUI) showPreview() error {
+	if !ui.splitting() {
+		return nil
+	}
+	e, _ := ui.entryList.SelectedItem().(entryItem)
+	return ui.preview.show(e)
+}
+
+// openToday opens the editor on today's entry, or on a new entry if nothing has been written today.
+func (ui JournalUI) openToday() (tea.Model, tea.Cmd) {
+	m, err := initDayEditorUI(entryItem{}, ui.jr)
+	if err != nil {
+		return ui, func() tea.Msg { return errMsg{err} }
+	}
+	return m, m.Init()
+}
+
+// onDay keeps the items written on the day the list is limited to, if it is.
+func (ui JournalUI) onDay(items []list.Item) []list.Item {
+	if ui.day.IsZero() {
+		return items
+	}
+
+	kept := make([]list.Item, 0, len(items))
+	for _, item := range items {
+		if e, ok := item.(entryItem); ok && startOfDay(e.CreateTime).Equal(ui.day) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// selectedDay is the day of the selected entry, or today if there isn't one.
+func (ui JournalUI) selectedDay() time.Time {
+	if e, ok := ui.entryList.SelectedItem().(entryItem); ok {
+		return e.CreateTime
+	}
+	return time.Now()
+}
+
+// promptDraft asks whether to recover the first of the drafts, if there are any left.
+func (ui *JournalUI) promptDraft() {
+	if len(ui.drafts) == 0 {
+		return
+	}
+
+	ui.dialog = newDialog(recoverDraftAction,
+		fmt.Sprintf("Recover the unsaved draft from %s?", ui.drafts[0].UpdateTime.Format(journalTimeLayout)),
+		"Recover", "Not now")
+}
+
+// answered acts on the answer to one of the list's dialogs. A draft that isn't recovered can
+// be discarded, or else it is asked about again next time.
+func (ui JournalUI) answered(msg dialogMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.action {
+	case deleteEntryAction:
+		if msg.confirmed {
+			cmd = deleteEntryCmd(ui.deleting.ID, ui.jr)
+		}
+		ui.deleting = entryItem{}
+	case recoverDraftAction:
+		if !msg.confirmed {
+			ui.dialog = newDialog(discardDraftAction, "Discard the draft? It can't be recovered afterwards.", "Discard", "Keep for later")
+			return ui, nil
+		}
+
+		d := ui.drafts[0]
+		var e entryItem
+		if d.EntryID != 0 {
+			// the draft of an entry deleted since is recovered as a new entry.
+			if entry, err := ui.jr.GetEntry(d.EntryID); err == nil {
+				e = entryItem{entry}
+			}
+		}
+		m := initDraftEditorUI(e, ui.jr, d)
+		return m, m.Init()
+	case discardDraftAction:
+		if msg.confirmed {
+			cmd = deleteDraftCmd(ui.drafts[0].ID, ui.jr)
+		}
+		ui.drafts = ui.drafts[1:]
+		ui.promptDraft()
+	}
+
+	return ui, cmd
+}
+
+// journalTitle renders the list title along with the last known sync state.
+func journalTitle(jr *jrnl.Journal) string {
+	title := "Journal Entries"
+	if !jr.SyncEnabled() {
+		return title
+	}
+
+	status := jr.LastSyncStatus()
+	switch {
+	case status.LastSync.IsZero():
+		return title + " • not synced"
+	case status.Conflicts > 0:
+		return fmt.Sprintf("%s • %d conflicts", title, status.Conflicts)
+	case status.ToPush > 0 || status.ToPull > 0:
+		return fmt.Sprintf("%s • %d↑ %d↓", title, status.ToPush, status.ToPull)
+	default:
+		return title + " • synced"
+	}
 }
 
 func newEntryList(jr *jrnl.Journal) ([]list.Item, error) {
@@ -164,19 +425,54 @@ func newEntryList(jr *jrnl.Journal) ([]list.Item, error) {
 func entriesToItems(entries []jrnl.Entry) []list.Item {
 	items := make([]list.Item, 0, len(entries))
 	for _, entry := range entries {
-		items = append(items, list.Item(entryItem(entry)))
+		items = append(items, list.Item(entryItem{entry}))
 	}
 
 	return items
 }
 
 type entryItem struct {
-	ID         int
-	Content    string
-	CreateTime time.Time
-	UpdateTime time.Time
+	jrnl.Entry
+}
+
+func (i entryItem) Title() string {
+	title := i.CreateTime.Format(journalTimeLayout)
+	if i.Starred {
+		title += " ★"
+	}
+	if i.Mood != 0 {
+		title += " " + moodIcon(i.Mood)
+	}
+	return title
+}
+
+func (i entryItem) Description() string {
+	if i.Entry.Title != "" {
+		return i.Entry.Title
+	}
+	return i.Content
+}
+
+// markdown is the entry's markdown with the values of its fields, if it has any, above it.
+func (i entryItem) markdown() string {
+	if len(i.Fields) == 0 {
+		return i.Markdown()
+	}
+
+	names := make([]string, 0, len(i.Fields))
+	for name := range i.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		values = append(values, fmt.Sprintf("**%s** %s", name, i.Fields[name]))
+	}
+
+	return fmt.Sprintf("> _%s_ · %s\n\n%s", i.Schema, strings.Join(values, " · "), i.Markdown())
 }
 
-func (i entryItem) Title() string       { return i.CreateTime.Format(journalTimeLayout) }
-func (i entryItem) Description() string { return i.Content }
-func (i entryItem) FilterValue() string { return i.CreateTime.Format(journalTimeLayout) }
+func (i entryItem) FilterValue() string {
+	return strings.Join(append([]string{i.CreateTime.Format(journalTimeLayout), i.Entry.Title}, i.Tags...), " ")
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MemoriesUI implements tea.Model. It lists the entries written on today's date in earlier years.
+type MemoriesUI struct {
+	memoryList list.Model
+	jr         *jrnl.Journal
+}
+
+// InitMemoriesUI initializes the "on this day" list.
+func InitMemoriesUI(jr *jrnl.Journal) (tea.Model, error) {
+	now := time.Now()
+	entries, err := jr.OnThisDay(now)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]list.Item, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, memoryItem{entryItem{e}, now})
+	}
+
+	ui := MemoriesUI{
+		memoryList: list.New(items, list.NewDefaultDelegate(), 0, 0),
+		jr:         jr,
+	}
+	ui.memoryList.Title = "On this day, " + now.Format("2 January")
+	ui.memoryList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{Keymap.Random, Keymap.Back}
+	}
+	top, right, bottom, left := DocStyle.GetMargin()
+	ui.memoryList.SetSize(WindowSize.Width-left-right, WindowSize.Height-top-bottom-1)
+
+	return ui, nil
+}
+
+// Init ...
+func (ui MemoriesUI) Init() tea.Cmd {
+	return nil
+}
+
+// Update ...
+func (ui MemoriesUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		WindowSize = msg
+		top, right, bottom, left := DocStyle.GetMargin()
+		ui.memoryList.SetSize(msg.Width-left-right, msg.Height-top-bottom-1)
+	case errMsg:
+		log.Printf("ERROR: %s\n", msg.Error())
+	case tea.KeyMsg:
+		// keys go to the filter while it's being typed.
+		if ui.memoryList.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, Keymap.ForceQuit):
+			return ui, tea.Quit
+		case key.Matches(msg, Keymap.Back) && ui.memoryList.FilterState() == list.Unfiltered:
+			m, err := initHomeUI(ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m, m.Init()
+		case key.Matches(msg, Keymap.Random):
+			return openRandomEntry(ui, ui.jr)
+		case key.Matches(msg, Keymap.Enter):
+			i, ok := ui.memoryList.SelectedItem().(memoryItem)
+			if !ok {
+				return ui, nil
+			}
+			m, err := InitEntryUI(i.entryItem, ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m.Update(WindowSize)
+		}
+	}
+
+	ui.memoryList, cmd = ui.memoryList.Update(msg)
+	return ui, cmd
+}
+
+// View returns the text UI to be output to the terminal.
+func (ui MemoriesUI) View() string {
+	return DocStyle.Render(ui.memoryList.View() + "\n")
+}
+
+// openRandomEntry opens an entry picked at random from before today, or stays on ui if there isn't one.
+func openRandomEntry(ui tea.Model, jr *jrnl.Journal) (tea.Model, tea.Cmd) {
+	e, err := jr.RandomEntry(time.Now())
+	if err != nil {
+		return ui, func() tea.Msg { return errMsg{err} }
+	}
+
+	m, err := InitEntryUI(entryItem{e}, jr)
+	if err != nil {
+		return ui, func() tea.Msg { return errMsg{err} }
+	}
+	return m.Update(WindowSize)
+}
+
+// memoryItem is an entry listed with how long ago it was written.
+type memoryItem struct {
+	entryItem
+	now time.Time
+}
+
+func (i memoryItem) Title() string {
+	years := i.now.Year() - i.CreateTime.In(i.now.Location()).Year()
+	ago := "A year ago"
+	if years != 1 {
+		ago = fmt.Sprintf("%d years ago", years)
+	}
+	return ago + " • " + i.entryItem.Title()
+}
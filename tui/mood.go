@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	// moodScale is the mood scale from the config, worst first.
+	moodScale = jrnl.DefaultMoodScale
+
+	// askMood is whether the editor asks for a mood when an entry without one is saved.
+	askMood = true
+)
+
+// moodMsg is sent when a mood has been picked, or with mood 0 when picking was skipped.
+type moodMsg struct {
+	mood int
+}
+
+var moodKeys = struct {
+	Skip key.Binding
+}{
+	Skip: key.NewBinding(key.WithKeys("enter", "esc", " ")),
+}
+
+// moodPicker asks for a mood, picked with a single key: 1 for the first level of the scale
+// and so on, 0 for the tenth. Like a dialog it is handed every key press while it is open.
+type moodPicker struct {
+	message string
+	open    bool
+}
+
+func newMoodPicker(message string) moodPicker {
+	return moodPicker{message: message, open: true}
+}
+
+// Update handles a key press, closing the picker and answering with a moodMsg once a mood has
+// been picked or skipped. Other keys are ignored.
+func (p moodPicker) Update(msg tea.KeyMsg) (moodPicker, tea.Cmd) {
+	mood := 0
+	if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 {
+		if n, err := strconv.Atoi(string(msg.Runes)); err == nil {
+			if n == 0 {
+				n = 10
+			}
+			mood = n
+		}
+	}
+
+	switch {
+	case mood > 0 && mood <= len(moodScale):
+	case key.Matches(msg, moodKeys.Skip):
+		mood = 0
+	default:
+		return p, nil
+	}
+
+	p.open = false
+	return p, func() tea.Msg { return moodMsg{mood} }
+}
+
+// View renders the picker centered in a width by height area.
+func (p moodPicker) View(width, height int) string {
+	levels := make([]string, 0, len(moodScale))
+	for i, l := range moodScale {
+		levels = append(levels, fmt.Sprintf("%d %s", (i+1)%10, l))
+	}
+
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		p.message,
+		"",
+		strings.Join(levels, "   "),
+		"",
+		HelpStyle(moodKeysHelp(len(moodScale))+" pick • enter skip"),
+	)
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, dialogStyle.Render(body))
+}
+
+// moodKeysHelp names the keys that pick one of n levels, 0 standing for the tenth.
+func moodKeysHelp(n int) string {
+	switch {
+	case n == 1:
+		return "1"
+	case n < 10:
+		return fmt.Sprintf("1-%d", n)
+	}
+	return "1-9, 0"
+}
+
+// moodIcon is how mood is shown in lists, its emoji, or its label if it has none.
+func moodIcon(mood int) string {
+	if mood < 1 {
+		return ""
+	}
+	if mood > len(moodScale) {
+		return strconv.Itoa(mood)
+	}
+	switch l := moodScale[mood-1]; {
+	case l.Emoji != "":
+		return l.Emoji
+	case l.Label != "":
+		return l.Label
+	}
+	return strconv.Itoa(mood)
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// outlineWidth is the width of the outline panel, border included.
+const outlineWidth = 32
+
+var (
+	outlineStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderLeft(true).
+			BorderForeground(lipgloss.Color("241")).
+			Padding(0, 1)
+	outlineCursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("62")).Bold(true)
+)
+
+var outlineKeys = struct {
+	Up    key.Binding
+	Down  key.Binding
+	Jump  key.Binding
+	Close key.Binding
+}{
+	Up:    key.NewBinding(key.WithKeys("up", "k")),
+	Down:  key.NewBinding(key.WithKeys("down", "j")),
+	Jump:  key.NewBinding(key.WithKeys("enter")),
+	Close: key.NewBinding(key.WithKeys("esc", "o")),
+}
+
+// outlinePanel lists the headings of an entry to jump to. Like a dialog it is handed every
+// key press while it is open.
+type outlinePanel struct {
+	open     bool
+	headings []jrnl.Heading
+	// lines are the lines of the rendered entry each heading is on, -1 for one that wasn't found.
+	lines  []int
+	cursor int
+}
+
+// Update handles a key press, returning the line of the rendered entry to jump to, or -1.
+func (p outlinePanel) Update(msg tea.KeyMsg) (outlinePanel, int) {
+	switch {
+	case key.Matches(msg, outlineKeys.Up):
+		p.cursor = max(0, p.cursor-1)
+	case key.Matches(msg, outlineKeys.Down):
+		// an entry without headings leaves the cursor at 0 rather than -1.
+		p.cursor = max(0, min(len(p.headings)-1, p.cursor+1))
+	case key.Matches(msg, outlineKeys.Jump):
+		if p.cursor >= 0 && p.cursor < len(p.lines) {
+			return p, p.lines[p.cursor]
+		}
+	case key.Matches(msg, outlineKeys.Close):
+		p.open = false
+	}
+	return p, -1
+}
+
+// View renders the panel height lines high.
+func (p outlinePanel) View(height int) string {
+	width := outlineWidth - outlineStyle.GetHorizontalFrameSize()
+
+	var b strings.Builder
+	b.WriteString(statsHeadingStyle.Render("Outline") + "\n\n")
+	if len(p.headings) == 0 {
+		b.WriteString(HelpStyle("No headings"))
+	}
+	for i, h := range p.headings {
+		line := []rune(strings.Repeat("  ", h.Level-1) + h.Text)
+		if len(line) > width {
+			line = append(line[:width-1], '…')
+		}
+		if i == p.cursor {
+			b.WriteString(outlineCursorStyle.Render(string(line)) + "\n")
+		} else {
+			b.WriteString(string(line) + "\n")
+		}
+	}
+
+	return outlineStyle.Height(height).Render(strings.TrimRight(b.String(), "\n"))
+}
+
+// headingLines finds the lines of the rendered entry the headings are on, in order.
+func headingLines(rendered string, headings []jrnl.Heading) []int {
+	lines := strings.Split(rendered, "\n")
+	found := make([]int, len(headings))
+	from := 0
+	for i, h := range headings {
+		found[i] = -1
+		for l := from; l < len(lines); l++ {
+			if strings.Contains(ansiCodes.ReplaceAllString(lines[l], ""), h.Text) {
+				found[i], from = l, l+1
+				break
+			}
+		}
+	}
+	return found
+}
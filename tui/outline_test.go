@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/actatum/jrnl"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOutlinePanel_Update(t *testing.T) {
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	t.Run("no headings", func(t *testing.T) {
+		p := outlinePanel{open: true}
+		p, _ = p.Update(down)
+		if p.cursor != 0 {
+			t.Errorf("cursor after down = %d, want 0", p.cursor)
+		}
+		if _, line := p.Update(enter); line != -1 {
+			t.Errorf("Update(enter) = %d, want -1", line)
+		}
+	})
+
+	t.Run("headings", func(t *testing.T) {
+		p := outlinePanel{
+			open:     true,
+			headings: []jrnl.Heading{{Level: 1, Text: "a"}, {Level: 2, Text: "b"}},
+			lines:    []int{2, 7},
+		}
+		for i := 0; i < 3; i++ {
+			p, _ = p.Update(down)
+		}
+		if _, line := p.Update(enter); line != 7 {
+			t.Errorf("Update(enter) on the last heading = %d, want 7", line)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"syscall"
 
 	"golang.org/x/term"
@@ -11,12 +12,12 @@ import (
 // CreatePasswordPrompt prompts the user to create a new password for their journal.
 func CreatePasswordPrompt() (string, error) {
 	fmt.Println("Create a password for your journal...")
-	pw, err := term.ReadPassword(syscall.Stdin)
+	pw, err := readPassword()
 	if err != nil {
 		return "", err
 	}
 	fmt.Println("Re-enter your password...")
-	reentry, err := term.ReadPassword(syscall.Stdin)
+	reentry, err := readPassword()
 	if err != nil {
 		return "", err
 	}
@@ -31,10 +32,28 @@ func CreatePasswordPrompt() (string, error) {
 // EnterPasswordPrompt prompts the user to enter the password for their journal.
 func EnterPasswordPrompt() (string, error) {
 	fmt.Println("Enter your journal password...")
-	pw, err := term.ReadPassword(syscall.Stdin)
+	pw, err := readPassword()
 	if err != nil {
 		return "", err
 	}
 
 	return string(pw), nil
 }
+
+// readPassword reads a password without echoing it. When stdin isn't a terminal, because text
+// is being piped into jrnl, the password is read from the controlling terminal instead.
+func readPassword() ([]byte, error) {
+	if term.IsTerminal(syscall.Stdin) {
+		return term.ReadPassword(syscall.Stdin)
+	}
+
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal and there is no terminal to read the password from: %w", err)
+	}
+	defer func() {
+		_ = tty.Close()
+	}()
+
+	return term.ReadPassword(int(tty.Fd()))
+}
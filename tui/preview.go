@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// splitPaneMinWidth is the narrowest terminal the list is shown beside a preview in, below
+	// it the list takes the whole width.
+	splitPaneMinWidth = 90
+	// splitPaneListPercent is how much of the width the list takes beside the preview.
+	splitPaneListPercent = 40
+)
+
+var previewStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.NormalBorder()).
+	BorderLeft(true).
+	BorderForeground(lipgloss.Color("241")).
+	PaddingLeft(1)
+
+// entryPreview renders an entry beside the list or the editor.
+type entryPreview struct {
+	viewport viewport.Model
+	renderer *glamour.TermRenderer
+	// entry is the entry last shown, it's rendered again only once it or the width changes.
+	entry entryItem
+	width int
+	// lines is how many lines the rendered entry takes up.
+	lines int
+}
+
+// resize sets the size the preview fills, border included.
+func (p *entryPreview) resize(width, height int) error {
+	width -= previewStyle.GetHorizontalFrameSize()
+	p.viewport.Width = width
+	p.viewport.Height = height
+	if width == p.width && p.renderer != nil {
+		return nil
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(width-2),
+		glamour.WithEmoji(),
+	)
+	if err != nil {
+		return err
+	}
+	p.renderer = renderer
+	p.width = width
+	p.entry = entryItem{}
+
+	return nil
+}
+
+// show renders e in the preview, unless it's showing already.
+func (p *entryPreview) show(e entryItem) error {
+	if p.renderer == nil || (e.ID == p.entry.ID && e.UpdateTime.Equal(p.entry.UpdateTime) && e.Mood == p.entry.Mood) {
+		return nil
+	}
+
+	md := ""
+	if e.ID != 0 {
+		md = e.markdown()
+	}
+	if err := p.render(md); err != nil {
+		return err
+	}
+	p.viewport.GotoTop()
+	p.entry = e
+
+	return nil
+}
+
+// render renders the markdown md in the preview.
+func (p *entryPreview) render(md string) error {
+	str := ""
+	if md != "" {
+		var err error
+		if str, err = p.renderer.Render(md); err != nil {
+			return err
+		}
+	}
+	p.viewport.SetContent(str)
+	p.lines = strings.Count(str, "\n") + 1
+
+	return nil
+}
+
+// scrollTo scrolls the preview the fraction of the way down it, from 0 at the top to 1 at the bottom.
+func (p *entryPreview) scrollTo(fraction float64) {
+	p.viewport.SetYOffset(int(fraction * float64(max(0, p.lines-p.viewport.Height))))
+}
+
+// View renders the preview with its border.
+func (p entryPreview) View() string {
+	return previewStyle.Render(p.viewport.View())
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestJournalUI_SplitPane(t *testing.T) {
+	size, split := WindowSize, splitPane
+	t.Cleanup(func() { WindowSize, splitPane = size, split })
+	splitPane = true
+
+	jr := mustNewTestJournal(t)
+	if _, err := jr.CreateEntry("# hello\n\nthere"); err != nil {
+		t.Fatal(err)
+	}
+	m, err := InitJournalUI(jr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, right, _, left := DocStyle.GetMargin()
+	tests := []struct {
+		width     int
+		splitting bool
+		listWidth int
+	}{
+		{width: splitPaneMinWidth - 1, splitting: false, listWidth: splitPaneMinWidth - 1 - left - right},
+		{width: splitPaneMinWidth, splitting: true, listWidth: (splitPaneMinWidth - left - right) * splitPaneListPercent / 100},
+		{width: 60, splitting: false, listWidth: 60 - left - right},
+	}
+	for _, tt := range tests {
+		m, _ = m.Update(tea.WindowSizeMsg{Width: tt.width, Height: 30})
+		ui, ok := m.(JournalUI)
+		if !ok {
+			t.Fatalf("Update() returned a %T, want the list", m)
+		}
+		if ui.splitting() != tt.splitting {
+			t.Errorf("%d columns: splitting() = %v, want %v", tt.width, ui.splitting(), tt.splitting)
+		}
+		if w := ui.entryList.Width(); w != tt.listWidth {
+			t.Errorf("%d columns: list width = %d, want %d", tt.width, w, tt.listWidth)
+		}
+	}
+}
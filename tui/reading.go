@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var readingSeparatorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("62")).Bold(true)
+
+var readingKeys = struct {
+	PrevMonth key.Binding
+	NextMonth key.Binding
+}{
+	PrevMonth: key.NewBinding(key.WithKeys("[")),
+	NextMonth: key.NewBinding(key.WithKeys("]")),
+}
+
+// ReadingUI implements tea.Model. It flows a month of entries one after another through a
+// single viewport, oldest first and with a separator between days, to be read like a book.
+type ReadingUI struct {
+	jr       *jrnl.Journal
+	viewport viewport.Model
+	renderer *glamour.TermRenderer
+	// month is the start of the month being read.
+	month   time.Time
+	entries []jrnl.Entry
+	// offsets are the lines of the viewport's content each of the entries starts on, lines is
+	// how many lines there are in all.
+	offsets []int
+	lines   int
+	// from is the entry reading was started from, the one to go back to.
+	from entryItem
+}
+
+// InitReadingUI initializes reading mode on the month e was written in, starting at e.
+func InitReadingUI(e entryItem, jr *jrnl.Journal) (tea.Model, error) {
+	ui := ReadingUI{jr: jr, from: e}
+	ui.viewport = viewport.New(WindowSize.Width, WindowSize.Height-ui.verticalMarginHeight())
+
+	t := e.CreateTime.In(time.Local)
+	if err := ui.load(time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)); err != nil {
+		return nil, err
+	}
+	for i, entry := range ui.entries {
+		if entry.ID == e.ID {
+			ui.viewport.SetYOffset(ui.offsets[i])
+		}
+	}
+
+	return ui, nil
+}
+
+// load reads the entries of month and lays them out from the top.
+func (ui *ReadingUI) load(month time.Time) error {
+	entries, err := ui.jr.EntriesBetween(month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return err
+	}
+	ui.month = month
+	ui.entries = entries
+
+	if err = ui.render(); err != nil {
+		return err
+	}
+	ui.viewport.GotoTop()
+	return nil
+}
+
+// render lays out the entries for the width of the window.
+func (ui *ReadingUI) render() error {
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(WindowSize.Width-5),
+		glamour.WithEmoji(),
+	)
+	if err != nil {
+		return err
+	}
+	ui.renderer = renderer
+
+	if len(ui.entries) == 0 {
+		ui.offsets = nil
+		ui.setContent(DocStyle.Render("\n" + HelpStyle("Nothing written this month, [ and ] go to the months before and after.")))
+		return nil
+	}
+
+	var (
+		b     strings.Builder
+		day   string
+		lines int
+	)
+	// write keeps count of the lines written so far, so entries' offsets don't need the text rescanned.
+	write := func(s string) {
+		b.WriteString(s)
+		lines += strings.Count(s, "\n")
+	}
+	ui.offsets = make([]int, 0, len(ui.entries))
+	for _, e := range ui.entries {
+		// an entry starts at the separator when it's the first of its day.
+		ui.offsets = append(ui.offsets, lines)
+		t := e.CreateTime.In(time.Local)
+		if d := t.Format("Monday, 02 January 2006"); d != day {
+			day = d
+			write("\n" + DocStyle.Render(ui.separator(d)) + "\n")
+		}
+
+		heading := t.Format("3:04PM")
+		if e.Mood != 0 {
+			heading += " " + moodIcon(e.Mood)
+		}
+		write(DocStyle.Render(HelpStyle(heading)) + "\n")
+
+		str, rerr := ui.renderer.Render(entryItem{e}.markdown())
+		if rerr != nil {
+			return rerr
+		}
+		write(str)
+	}
+	ui.setContent(b.String())
+
+	return nil
+}
+
+func (ui *ReadingUI) setContent(s string) {
+	ui.viewport.SetContent(s)
+	ui.lines = strings.Count(s, "\n") + 1
+}
+
+// separator is a rule across the window with the day d in it.
+func (ui ReadingUI) separator(d string) string {
+	_, right, _, left := DocStyle.GetMargin()
+	label := " " + d + " "
+	side := max(0, WindowSize.Width-left-right-lipgloss.Width(label)) / 2
+	return readingSeparatorStyle.Render(strings.Repeat("─", side) + label + strings.Repeat("─", side))
+}
+
+// Init ...
+func (ui ReadingUI) Init() tea.Cmd {
+	return nil
+}
+
+// Update ...
+func (ui ReadingUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		WindowSize = msg
+		ui.viewport.Width = msg.Width
+		ui.viewport.Height = msg.Height - ui.verticalMarginHeight()
+		if err := ui.render(); err != nil {
+			return ui, func() tea.Msg { return errMsg{err} }
+		}
+	case errMsg:
+		log.Printf("ERROR: %s\n", msg.Error())
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, Keymap.Quit):
+			return ui, tea.Quit
+		case key.Matches(msg, Keymap.Back):
+			if ui.from.ID == 0 {
+				m, err := initHomeUI(ui.jr)
+				if err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+				return m, m.Init()
+			}
+			m, err := InitEntryUI(ui.from, ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m.Update(WindowSize)
+		case key.Matches(msg, readingKeys.PrevMonth):
+			if err := ui.load(ui.month.AddDate(0, -1, 0)); err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return ui, nil
+		case key.Matches(msg, readingKeys.NextMonth):
+			if err := ui.load(ui.month.AddDate(0, 1, 0)); err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return ui, nil
+		}
+	}
+
+	ui.viewport, cmd = ui.viewport.Update(msg)
+	return ui, cmd
+}
+
+// View returns the text UI to be output to the terminal.
+func (ui ReadingUI) View() string {
+	return fmt.Sprintf("%s\n%s\n%s\n%s", ui.headerView(), ui.viewport.View(), ui.footerView(), ui.helpView())
+}
+
+func (ui ReadingUI) headerView() string {
+	title := titleStyle.Render(ui.month.Format("January 2006"))
+	line := strings.Repeat("─", max(0, ui.viewport.Width-lipgloss.Width(title)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
+}
+
+func (ui ReadingUI) footerView() string {
+	info := infoStyle.Render(fmt.Sprintf("page %d of %d", ui.page(), ui.pages()))
+	line := strings.Repeat("─", max(0, ui.viewport.Width-lipgloss.Width(info)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, line, info)
+}
+
+func (ui ReadingUI) helpView() string {
+	return HelpStyle("\n • space/f next page • b previous page • ↑/k up • ↓/j down • [/] month • esc back • q quit\n")
+}
+
+// page is the page the top of the viewport is on.
+func (ui ReadingUI) page() int {
+	if ui.viewport.Height <= 0 {
+		return 1
+	}
+	return min(ui.pages(), ui.viewport.YOffset/ui.viewport.Height+1)
+}
+
+// pages is how many viewports the month's entries take up.
+func (ui ReadingUI) pages() int {
+	if ui.viewport.Height <= 0 {
+		return 1
+	}
+	return max(1, int(math.Ceil(float64(ui.lines)/float64(ui.viewport.Height))))
+}
+
+func (ui ReadingUI) verticalMarginHeight() int {
+	return lipgloss.Height(ui.headerView()) + lipgloss.Height(ui.footerView()) + lipgloss.Height(ui.helpView())
+}
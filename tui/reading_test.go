@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadingUI_Render(t *testing.T) {
+	size := WindowSize
+	t.Cleanup(func() { WindowSize = size })
+	// tall enough for the whole month to be in view.
+	WindowSize = tea.WindowSizeMsg{Width: 80, Height: 500}
+
+	jr := mustNewTestJournal(t)
+	day := time.Date(2023, time.March, 14, 9, 0, 0, 0, time.Local)
+	var entries []jrnl.Entry
+	for _, e := range []jrnl.Entry{
+		{Content: "first\n\nof the day", CreateTime: day},
+		{Content: "second of the day", CreateTime: day.Add(8 * time.Hour)},
+		{Content: "the next day", CreateTime: day.AddDate(0, 0, 1)},
+	} {
+		created, err := jr.CreateEntryFrom(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, created)
+	}
+
+	m, err := InitReadingUI(entryItem{entries[0]}, jr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui, ok := m.(ReadingUI)
+	if !ok {
+		t.Fatalf("InitReadingUI() returned a %T, want reading mode", m)
+	}
+	if len(ui.offsets) != len(entries) {
+		t.Fatalf("offsets = %v, want one per entry", ui.offsets)
+	}
+
+	lines := strings.Split(ansiCodes.ReplaceAllString(ui.viewport.View(), ""), "\n")
+	// the first entry of a day starts at the blank line above the day's separator, the
+	// others at their time, and each runs until the next starts.
+	starts := []string{"", day.Add(8 * time.Hour).Format("3:04PM"), ""}
+	texts := []string{"of the day", "second of the day", "the next day"}
+	for i, off := range ui.offsets {
+		end := len(lines)
+		if i+1 < len(ui.offsets) {
+			end = ui.offsets[i+1]
+		}
+		if off >= end {
+			t.Fatalf("offsets = %v, want them increasing and within the %d lines", ui.offsets, len(lines))
+		}
+		if start := strings.TrimSpace(lines[off]); start != starts[i] {
+			t.Errorf("entry %d starts on %q, want %q", i, start, starts[i])
+		}
+		if text := strings.Join(lines[off:end], "\n"); !strings.Contains(text, texts[i]) {
+			t.Errorf("entry %d's lines don't hold %q:\n%s", i, texts[i], text)
+		}
+	}
+}
+
+func TestReadingUI_Pages(t *testing.T) {
+	tests := []struct {
+		lines, height, offset int
+		page, pages           int
+	}{
+		{lines: 12, height: 5, offset: 0, page: 1, pages: 3},
+		{lines: 12, height: 5, offset: 7, page: 2, pages: 3},
+		{lines: 12, height: 5, offset: 10, page: 3, pages: 3},
+		{lines: 10, height: 5, offset: 10, page: 2, pages: 2},
+		{lines: 0, height: 5, offset: 0, page: 1, pages: 1},
+		{lines: 12, height: 0, offset: 3, page: 1, pages: 1},
+	}
+	for _, tt := range tests {
+		ui := ReadingUI{viewport: viewport.New(80, tt.height), lines: tt.lines}
+		ui.viewport.YOffset = tt.offset
+		if page, pages := ui.page(), ui.pages(); page != tt.page || pages != tt.pages {
+			t.Errorf("%d lines, %d high, at %d: page %d of %d, want %d of %d", tt.lines, tt.height, tt.offset, page, pages, tt.page, tt.pages)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	matchStyle        = lipgloss.NewStyle().Background(lipgloss.Color("#5f5f00")).Foreground(lipgloss.Color("230"))
+	currentMatchStyle = lipgloss.NewStyle().Background(lipgloss.Color("#d7af00")).Foreground(lipgloss.Color("16"))
+
+	// ansiCodes matches the escape sequences glamour styles its output with.
+	ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+)
+
+// entrySearch searches the text of a rendered entry.
+type entrySearch struct {
+	input textinput.Model
+	// typing is whether the query is being typed, the input takes every key press while it is.
+	typing bool
+	query  string
+	// hits are the lines of the rendered entry the matches of query are on, a line once for
+	// each match on it. current is the index of the match last jumped to.
+	hits    []int
+	current int
+}
+
+func newEntrySearch() entrySearch {
+	input := textinput.New()
+	input.Prompt = "/"
+	return entrySearch{input: input, typing: true}
+}
+
+// next moves to the match after the current one, or with forward false the one before,
+// wrapping around at either end. It returns the line of the match.
+func (s *entrySearch) next(forward bool) int {
+	if len(s.hits) == 0 {
+		return -1
+	}
+	if forward {
+		s.current = (s.current + 1) % len(s.hits)
+	} else {
+		s.current = (s.current - 1 + len(s.hits)) % len(s.hits)
+	}
+	return s.hits[s.current]
+}
+
+// first moves to the first match on or after line, or back to the first match if there are
+// none after it. It returns the line of the match.
+func (s *entrySearch) first(line int) int {
+	if len(s.hits) == 0 {
+		return -1
+	}
+	s.current = 0
+	for i, hit := range s.hits {
+		if hit >= line {
+			s.current = i
+			break
+		}
+	}
+	return s.hits[s.current]
+}
+
+// highlightMatches highlights the matches of query in rendered, ignoring case, with the
+// current'th match stood out from the rest. Lines with a match lose the rest of their styling.
+// It returns the highlighted text and the lines the matches are on.
+func highlightMatches(rendered, query string, current int) (string, []int) {
+	if query == "" {
+		return rendered, nil
+	}
+
+	q := []rune(query)
+	for i, r := range q {
+		q[i] = unicode.ToLower(r)
+	}
+	lines := strings.Split(rendered, "\n")
+	var hits []int
+	for i, line := range lines {
+		plain := []rune(ansiCodes.ReplaceAllString(line, ""))
+		starts := findFold(plain, q)
+		if len(starts) == 0 {
+			continue
+		}
+
+		var b strings.Builder
+		end := 0
+		for _, start := range starts {
+			style := matchStyle
+			if len(hits) == current {
+				style = currentMatchStyle
+			}
+			hits = append(hits, i)
+
+			b.WriteString(string(plain[end:start]))
+			b.WriteString(style.Render(string(plain[start : start+len(q)])))
+			end = start + len(q)
+		}
+		b.WriteString(string(plain[end:]))
+		lines[i] = b.String()
+	}
+
+	return strings.Join(lines, "\n"), hits
+}
+
+// findFold returns where the lower case query q starts in s, ignoring case, without overlaps.
+func findFold(s, q []rune) []int {
+	var starts []int
+	for i := 0; i+len(q) <= len(s); i++ {
+		match := true
+		for j, r := range q {
+			if unicode.ToLower(s[i+j]) != r {
+				match = false
+				break
+			}
+		}
+		if match {
+			starts = append(starts, i)
+			i += len(q) - 1
+		}
+	}
+	return starts
+}
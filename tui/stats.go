@@ -0,0 +1,290 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// statsBarWidth is the width of a full bar in the stats charts.
+	statsBarWidth = 30
+	// statsMonths is how many of the latest months the stats show averages for.
+	statsMonths = 12
+	// heatmapWeeks is the most weeks the heatmap shows, a year.
+	heatmapWeeks = 53
+)
+
+var (
+	statsHeadingStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("62"))
+	statsBarStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("62"))
+
+	// heatmapColors colour the heatmap's days from no writing to the most.
+	heatmapColors = []lipgloss.Color{"237", "#0e4429", "#006d32", "#26a641", "#39d353"}
+)
+
+// StatsUI implements tea.Model. It summarizes the writing and moods in the journal.
+type StatsUI struct {
+	jr       *jrnl.Journal
+	stats    jrnl.Stats
+	mood     jrnl.MoodReport
+	viewport viewport.Model
+	// loaded counts the reports that have come in, the stats and the moods.
+	loaded int
+}
+
+// InitStatsUI initializes the stats view. The numbers are worked out once it's showing.
+func InitStatsUI(jr *jrnl.Journal) tea.Model {
+	ui := StatsUI{jr: jr}
+	ui.viewport = viewport.New(WindowSize.Width, WindowSize.Height-ui.verticalMarginHeight())
+	return ui
+}
+
+// Init ...
+func (ui StatsUI) Init() tea.Cmd {
+	return tea.Batch(writingStatsCmd(ui.jr), moodReportCmd(ui.jr))
+}
+
+// Update ...
+func (ui StatsUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		WindowSize = msg
+		ui.viewport.Width = msg.Width
+		ui.viewport.Height = msg.Height - ui.verticalMarginHeight()
+		ui.viewport.SetContent(ui.content())
+	case writingStatsMsg:
+		ui.stats = msg.stats
+		ui.loaded++
+		ui.viewport.SetContent(ui.content())
+	case moodReportMsg:
+		ui.mood = msg.report
+		ui.loaded++
+		ui.viewport.SetContent(ui.content())
+	case errMsg:
+		log.Printf("ERROR: %s\n", msg.Error())
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, Keymap.Quit):
+			return ui, tea.Quit
+		case key.Matches(msg, Keymap.Back):
+			m, err := initHomeUI(ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m, m.Init()
+		}
+	}
+
+	ui.viewport, cmd = ui.viewport.Update(msg)
+	return ui, cmd
+}
+
+// View returns the text UI to be output to the terminal.
+func (ui StatsUI) View() string {
+	return fmt.Sprintf("%s\n%s", ui.viewport.View(), ui.helpView())
+}
+
+func (ui StatsUI) helpView() string {
+	return HelpStyle("\n • ↑/k up • ↓/j down • esc back • q quit\n")
+}
+
+func (ui StatsUI) verticalMarginHeight() int {
+	return lipgloss.Height(ui.helpView())
+}
+
+// content renders the stats to scroll through in the viewport.
+func (ui StatsUI) content() string {
+	if ui.loaded < 2 {
+		return DocStyle.Render("\nWorking out the stats...")
+	}
+
+	_, right, _, left := DocStyle.GetMargin()
+	width := WindowSize.Width - left - right
+
+	return DocStyle.Render(lipgloss.JoinVertical(lipgloss.Left, "", ui.writingView(width), "", ui.moodView(width)))
+}
+
+func (ui StatsUI) writingView(width int) string {
+	s := ui.stats
+	var b strings.Builder
+	b.WriteString(statsHeadingStyle.Render("Writing") + "\n\n")
+	fmt.Fprintf(&b, "%d entries and %d words on %s, %.0f words a day\n", s.Entries, s.Words, days(s.Days), s.WordsPerDay)
+	fmt.Fprintf(&b, "Streak %s, longest %s\n\n", days(s.CurrentStreak), days(s.LongestStreak))
+	if s.Entries == 0 {
+		return b.String()
+	}
+
+	b.WriteString(heatmap(s.ByDay, time.Now(), width) + "\n\n")
+
+	b.WriteString(statsHeadingStyle.Render("Most active hours") + "\n")
+	hours := make([]float64, len(s.ByHour))
+	var busiest float64
+	for h, n := range s.ByHour {
+		hours[h] = float64(n)
+		busiest = math.Max(busiest, hours[h])
+	}
+	b.WriteString(statsBarStyle.Render(jrnl.Sparkline(hours, 0, busiest)) + "\n")
+	b.WriteString(HelpStyle("0     6     12    18   23") + "\n\n")
+
+	b.WriteString(statsHeadingStyle.Render("By weekday") + "\n")
+	var most int
+	for _, n := range s.ByWeekday {
+		most = max(most, n)
+	}
+	for i := 1; i <= 7; i++ {
+		wd := time.Weekday(i % 7)
+		b.WriteString(countBar(wd.String()[:3], s.ByWeekday[wd], most, "entries") + "\n")
+	}
+
+	b.WriteString("\n" + statsHeadingStyle.Render("Words by month") + "\n")
+	months := s.ByMonth
+	if len(months) > statsMonths {
+		months = months[len(months)-statsMonths:]
+	}
+	most = 0
+	for _, m := range months {
+		most = max(most, m.Words)
+	}
+	for _, m := range months {
+		t, _ := time.Parse("2006-01", m.Month)
+		b.WriteString(countBar(t.Format("Jan 2006"), m.Words, most, "words") + "\n")
+	}
+
+	return b.String()
+}
+
+func (ui StatsUI) moodView(width int) string {
+	r := ui.mood
+	var b strings.Builder
+	b.WriteString(statsHeadingStyle.Render("Mood") + "\n\n")
+	if len(r.Points) == 0 {
+		b.WriteString("No moods recorded yet, press m on an entry to add one.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%d moods, on average %s\n\n", len(r.Points), moodAverage(r.Average))
+	daily := r.Daily(width)
+	b.WriteString(statsBarStyle.Render(jrnl.Sparkline(daily, 1, float64(len(moodScale)))) + "\n")
+	from, to := r.From.Format("2006-01-02"), r.To.AddDate(0, 0, -1).Format("2006-01-02")
+	b.WriteString(HelpStyle(from+strings.Repeat(" ", max(1, len(daily)-len(from)-len(to)))+to) + "\n\n")
+
+	b.WriteString(statsHeadingStyle.Render("By weekday") + "\n")
+	for i := 1; i <= 7; i++ {
+		wd := time.Weekday(i % 7)
+		b.WriteString(moodBar(wd.String()[:3], r.ByWeekday[wd]) + "\n")
+	}
+
+	b.WriteString("\n" + statsHeadingStyle.Render("By month") + "\n")
+	months := r.ByMonth
+	if len(months) > statsMonths {
+		months = months[len(months)-statsMonths:]
+	}
+	for _, m := range months {
+		b.WriteString(moodBar(m.Month.Format("Jan 2006"), m.Average) + "\n")
+	}
+
+	return b.String()
+}
+
+// heatmap draws a calendar of the days up to now coloured by how much was written on them,
+// a column a week and a row a weekday, with as many weeks as fit in width.
+func heatmap(byDay []jrnl.DailyStats, now time.Time, width int) string {
+	words := make(map[string]int, len(byDay))
+	for _, d := range byDay {
+		words[d.Date] = d.Words
+	}
+
+	const labelWidth = 4
+	weeks := min(heatmapWeeks, max(1, (width-labelWidth)/2))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// weeks start on Monday, the last column is the current week.
+	start := today.AddDate(0, 0, -(int(today.Weekday())+6)%7-7*(weeks-1))
+
+	// the busiest day shown sets the scale.
+	most := 0
+	for d := start; !d.After(today); d = d.AddDate(0, 0, 1) {
+		most = max(most, words[d.Format("2006-01-02")])
+	}
+
+	months := []rune(strings.Repeat(" ", labelWidth+2*weeks))
+	rows := make([]strings.Builder, 7)
+	for w := 0; w < weeks; w++ {
+		for wd := 0; wd < 7; wd++ {
+			d := start.AddDate(0, 0, 7*w+wd)
+			if d.After(today) {
+				break
+			}
+			if d.Day() == 1 || (w == 0 && wd == 0) {
+				label := []rune(d.Format("Jan"))
+				if pos := labelWidth + 2*w; pos+len(label) <= len(months) {
+					copy(months[pos:], label)
+				}
+			}
+
+			level := 0
+			if n := words[d.Format("2006-01-02")]; n > 0 && most > 0 {
+				level = 1 + min(len(heatmapColors)-2, (n-1)*(len(heatmapColors)-1)/most)
+			}
+			rows[wd].WriteString(lipgloss.NewStyle().Foreground(heatmapColors[level]).Render("■") + " ")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(HelpStyle(strings.TrimRight(string(months), " ")) + "\n")
+	for wd := range rows {
+		label := ""
+		if wd%2 == 0 {
+			label = time.Weekday((wd + 1) % 7).String()[:3]
+		}
+		fmt.Fprintf(&b, "%s%s\n", HelpStyle(fmt.Sprintf("%-*s", labelWidth, label)), rows[wd].String())
+	}
+
+	legend := make([]string, 0, len(heatmapColors))
+	for _, c := range heatmapColors {
+		legend = append(legend, lipgloss.NewStyle().Foreground(c).Render("■"))
+	}
+	b.WriteString(HelpStyle(strings.Repeat(" ", labelWidth)+"less ") + strings.Join(legend, " ") + HelpStyle(" more"))
+
+	return b.String()
+}
+
+// countBar is a labelled bar as long as n is of most.
+func countBar(label string, n, most int, unit string) string {
+	length := 0
+	if most > 0 {
+		length = int(math.Round(float64(n) / float64(most) * statsBarWidth))
+	}
+	return fmt.Sprintf("%-8s %s %s", label, statsBarStyle.Render(strings.Repeat("█", length)), HelpStyle(fmt.Sprintf("%d %s", n, unit)))
+}
+
+// moodBar is a labelled bar as long as the average mood avg, empty if avg is 0.
+func moodBar(label string, avg float64) string {
+	if avg == 0 {
+		return fmt.Sprintf("%-8s %s", label, HelpStyle("-"))
+	}
+	n := int(math.Round(avg / float64(len(moodScale)) * statsBarWidth))
+	return fmt.Sprintf("%-8s %s %s", label, statsBarStyle.Render(strings.Repeat("█", n)), moodAverage(avg))
+}
+
+// moodAverage shows an average mood with the level it's closest to.
+func moodAverage(avg float64) string {
+	return fmt.Sprintf("%.1f %s", avg, moodIcon(int(math.Round(avg))))
+}
+
+func days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
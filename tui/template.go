@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/actatum/jrnl"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TemplateUI implements tea.Model. It lists the templates to start a new entry from.
+type TemplateUI struct {
+	templateList list.Model
+	jr           *jrnl.Journal
+}
+
+// InitTemplateUI initializes the template picker.
+func InitTemplateUI(jr *jrnl.Journal) (tea.Model, error) {
+	basePath, err := BasePath()
+	if err != nil {
+		return nil, err
+	}
+	templates, err := jr.ListTemplates(TemplateDir(basePath))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]list.Item, 0, len(templates))
+	for _, t := range templates {
+		items = append(items, templateItem{t})
+	}
+
+	ui := TemplateUI{
+		templateList: list.New(items, list.NewDefaultDelegate(), 0, 0),
+		jr:           jr,
+	}
+	ui.templateList.Title = "New entry from template"
+	top, right, bottom, left := DocStyle.GetMargin()
+	ui.templateList.SetSize(WindowSize.Width-left-right, WindowSize.Height-top-bottom-1)
+
+	return ui, nil
+}
+
+// Init ...
+func (ui TemplateUI) Init() tea.Cmd {
+	return nil
+}
+
+// Update ...
+func (ui TemplateUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		WindowSize = msg
+		top, right, bottom, left := DocStyle.GetMargin()
+		ui.templateList.SetSize(msg.Width-left-right, msg.Height-top-bottom-1)
+	case errMsg:
+		log.Printf("ERROR: %s\n", msg.Error())
+	case tea.KeyMsg:
+		// keys go to the filter while it's being typed.
+		if ui.templateList.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, Keymap.ForceQuit):
+			return ui, tea.Quit
+		case key.Matches(msg, Keymap.Back):
+			m, err := initHomeUI(ui.jr)
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+			return m, m.Init()
+		case key.Matches(msg, Keymap.Enter):
+			t, ok := ui.templateList.SelectedItem().(templateItem)
+			if !ok {
+				return ui, func() tea.Msg {
+					return errMsg{fmt.Errorf("failed type assertion on templateList item")}
+				}
+			}
+			content, err := ui.jr.RenderTemplate(t.Template, time.Now())
+			if err != nil {
+				return ui, func() tea.Msg { return errMsg{err} }
+			}
+
+			if dailyNotes {
+				m, err := initDayEditorUI(entryItem{jrnl.Entry{Content: content}}, ui.jr)
+				if err != nil {
+					return ui, func() tea.Msg { return errMsg{err} }
+				}
+				return m, m.Init()
+			}
+
+			// the rendered template counts as the starting point, so leaving it untouched isn't an unsaved change.
+			m := InitEditorUI(entryItem{jrnl.Entry{Content: content}}, ui.jr, true)
+			return m, m.Init()
+		}
+	}
+
+	ui.templateList, cmd = ui.templateList.Update(msg)
+	return ui, cmd
+}
+
+// View returns the text UI to be output to the terminal.
+func (ui TemplateUI) View() string {
+	return DocStyle.Render(ui.templateList.View() + "\n")
+}
+
+type templateItem struct {
+	jrnl.Template
+}
+
+func (i templateItem) Title() string {
+	return i.Name
+}
+
+// Description is the first line of the template with any markdown heading marks removed.
+func (i templateItem) Description() string {
+	for _, line := range strings.Split(i.Content, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "# ")); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func (i templateItem) FilterValue() string {
+	return i.Name
+}
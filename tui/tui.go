@@ -2,9 +2,11 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/actatum/jrnl"
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,7 +14,7 @@ import (
 
 // Run starts the tui program.
 func Run() error {
-	basePath, err := getBasePath()
+	basePath, err := BasePath()
 	if err != nil {
 		return err
 	}
@@ -34,8 +36,7 @@ func Run() error {
 		}()
 	}
 
-	dbPath := basePath + "/db"
-	jr, err := jrnl.NewJournal(dbPath)
+	jr, cfg, err := OpenJournal(basePath)
 	if err != nil {
 		return err
 	}
@@ -46,6 +47,133 @@ func Run() error {
 		}
 	}(jr)
 
+	if cfg.Backup != nil && cfg.Backup.OnStartup {
+		if path, berr := Backup(jr, *cfg.Backup, basePath); berr != nil {
+			log.Printf("ERROR: startup backup: %v\n", berr)
+		} else {
+			log.Printf("backed up journal to %s\n", path)
+		}
+	}
+
+	dailyNotes = cfg.DailyNotes
+	moodScale = cfg.Mood.MoodScale()
+	askMood = cfg.Mood == nil || !cfg.Mood.Off
+
+	m, err := initStartUI(jr, cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// initStartUI initializes the view the TUI opens on, the entries written on today's date in
+// earlier years if the config asks for them and there are any, or else the list. Unsaved
+// drafts are asked about in the list, so it opens on the list whenever there are any.
+func initStartUI(jr *jrnl.Journal, cfg jrnl.Config) (tea.Model, error) {
+	drafts, err := jr.ListDrafts()
+	if err != nil {
+		return nil, err
+	}
+	if len(drafts) > 0 {
+		return InitJournalUI(jr)
+	}
+
+	if cfg.OnThisDay {
+		m, err := InitMemoriesUI(jr)
+		if err != nil {
+			return nil, err
+		}
+		if memories, ok := m.(MemoriesUI); ok && len(memories.memoryList.Items()) > 0 {
+			return m, nil
+		}
+	}
+
+	return initHomeUI(jr)
+}
+
+// OpenJournal opens the journal stored under basePath, prompting the user to
+// create or enter its password, and attaches a sync remote if one is configured.
+func OpenJournal(basePath string) (*jrnl.Journal, jrnl.Config, error) {
+	return openJournal(basePath, jrnl.NewJournal, unlock)
+}
+
+// OpenJournalWithPassword opens the journal stored under basePath like OpenJournal, but unlocks
+// it with password, waiting up to wait for another jrnl with it open to close it. Commands run
+// from scripts ask for the password before opening the journal, so that each keeps it open
+// only while it works and those run at the same time take turns.
+func OpenJournalWithPassword(basePath, password string, wait time.Duration) (*jrnl.Journal, jrnl.Config, error) {
+	open := func(dbPath string) (*jrnl.Journal, error) {
+		return jrnl.NewJournalWait(dbPath, wait)
+	}
+	return openJournal(basePath, open, func(jr *jrnl.Journal) error {
+		initialized, err := jr.IsInitialized()
+		if err != nil {
+			return err
+		}
+		if !initialized {
+			return errors.New("the journal has no password yet, run jrnl to create one")
+		}
+		return jr.Auth(password)
+	})
+}
+
+func openJournal(basePath string, open func(dbPath string) (*jrnl.Journal, error), unlockJournal func(jr *jrnl.Journal) error) (*jrnl.Journal, jrnl.Config, error) {
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return nil, jrnl.Config{}, err
+	}
+
+	cfg, err := jrnl.LoadConfig(basePath + "/config.json")
+	if err != nil {
+		return nil, cfg, err
+	}
+
+	jr, err := open(basePath + "/db")
+	if err != nil {
+		return nil, cfg, err
+	}
+
+	if err = unlockJournal(jr); err != nil {
+		_ = jr.Close()
+		return nil, cfg, err
+	}
+
+	jr.UseMoodScale(cfg.Mood.MoodScale())
+
+	if cfg.Sync != nil {
+		var store *jrnl.S3Store
+		store, err = jrnl.NewS3Store(*cfg.Sync)
+		if err != nil {
+			_ = jr.Close()
+			return nil, cfg, err
+		}
+		jr.EnableSync(store)
+	}
+
+	if cfg.Git != nil {
+		if cfg.Git.Dir == "" {
+			cfg.Git.Dir = basePath + "/git"
+		}
+
+		var repo *jrnl.GitRepo
+		repo, err = jrnl.OpenGitRepo(*cfg.Git)
+		if err == nil {
+			err = jr.EnableGitHistory(repo)
+		}
+		if err != nil {
+			_ = jr.Close()
+			return nil, cfg, err
+		}
+	}
+
+	return jr, cfg, nil
+}
+
+func unlock(jr *jrnl.Journal) error {
 	initialized, err := jr.IsInitialized()
 	if err != nil {
 		return err
@@ -71,19 +199,27 @@ func Run() error {
 		}
 	}
 
-	err = jr.Auth(pw)
-	if err != nil {
-		return err
+	return jr.Auth(pw)
+}
+
+// Backup writes a backup of jr to the configured directory, defaulting to
+// basePath/backups, and rotates out old backups.
+func Backup(jr *jrnl.Journal, cfg jrnl.BackupConfig, basePath string) (string, error) {
+	if cfg.Dir == "" {
+		cfg.Dir = basePath + "/backups"
+	}
+	if cfg.RetentionPolicy == (jrnl.RetentionPolicy{}) {
+		cfg.RetentionPolicy = jrnl.DefaultRetentionPolicy
 	}
 
-	m, err := InitJournalUI(jr)
+	path, err := jr.BackupToDir(cfg.Dir)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if _, err = tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
-		return err
+	if _, err = jrnl.RotateBackups(cfg.Dir, cfg.RetentionPolicy); err != nil {
+		return path, err
 	}
 
-	return nil
+	return path, nil
 }